@@ -4,9 +4,8 @@ import (
 	"bepaid-sdk/service/vo"
 	"bytes"
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"io"
+	"log"
 	"net/http"
 	"strings"
 )
@@ -18,85 +17,136 @@ const (
 	voids          = "/transactions/voids"
 	refunds        = "/transactions/refunds"
 
+	statusUid        = "/transactions/"
 	statusTrackingId = "/v2/transactions/tracking_id/"
 )
 
 type Api struct {
 	client  *http.Client
 	baseUrl string
-	auth    string
+	shop    string
+	auth    Authenticator
+
+	// set Test field of every request implementing contracts.RequestSetTest
+	testMode bool
+
+	retry RetryPolicy
+
+	// default language of requests implementing contracts.RequestSetLanguage and Accept-Language header
+	language vo.Language
+
+	logger *log.Logger
+
+	breaker *CircuitBreaker
 }
 
 func (a *Api) StatusByUid(ctx context.Context, uid string) (*http.Response, error) {
-	return a.sendRequest(ctx, http.MethodGet, uid, nil)
+	return a.get(ctx, statusUid, uid)
 }
 
 func (a *Api) StatusByTrackingId(ctx context.Context, trackingId string) (*http.Response, error) {
-	return a.sendRequest(ctx, http.MethodGet, statusTrackingId+trackingId, nil)
+	return a.get(ctx, statusTrackingId, trackingId)
 }
 
 func NewApi(client *http.Client, baseUrl, username, password string) *Api {
 	return &Api{
 		client:  client,
 		baseUrl: strings.TrimSuffix(baseUrl, "/"),
-		auth:    "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))}
+		shop:    username,
+		auth:    BasicAuth(username, password)}
+}
+
+// WithAuthenticator replaces basic authentication with username and password of NewApi, see Authenticator
+func (a *Api) WithAuthenticator(auth Authenticator) *Api {
+	a.auth = auth
+	return a
+}
+
+// WithTestMode makes every payment and authorization a test transaction
+func (a *Api) WithTestMode(testMode bool) *Api {
+	a.testMode = testMode
+	return a
+}
+
+// WithLanguage asks the gateway for messages in language. Language of payment and authorization requests
+// set by WithLanguage of the request has priority
+func (a *Api) WithLanguage(language vo.Language) *Api {
+	a.language = language
+	return a
+}
+
+// WithLogger logs method, path, status and duration of every http request
+func (a *Api) WithLogger(logger *log.Logger) *Api {
+	a.logger = logger
+	return a
+}
+
+// WithRetryPolicy enables retries of status requests, see RetryPolicy
+func (a *Api) WithRetryPolicy(retry RetryPolicy) *Api {
+	a.retry = retry
+	return a
+}
+
+// WithCircuitBreaker fails requests fast while the gateway is failing, see CircuitBreaker.
+// Circuits of this Api are keyed by the shop set by WithShop or by username (shop id) of NewApi
+func (a *Api) WithCircuitBreaker(breaker *CircuitBreaker) *Api {
+	a.breaker = breaker
+	return a
 }
 
 func (a *Api) Payment(ctx context.Context, payment vo.PaymentRequest) (*http.Response, error) {
-	return a.sendRequest(ctx, http.MethodPost, payments, &payment)
+	return a.post(ctx, payments, &payment)
 }
 
 func (a *Api) Authorization(ctx context.Context, authorization vo.AuthorizationRequest) (*http.Response, error) {
-	return a.sendRequest(ctx, http.MethodPost, authorizations, &authorization)
+	return a.post(ctx, authorizations, &authorization)
 }
 
 func (a *Api) Capture(ctx context.Context, capture vo.CaptureRequest) (*http.Response, error) {
-	return a.sendRequest(ctx, http.MethodPost, captures, &capture)
+	return a.post(ctx, captures, &capture)
 }
 
 func (a *Api) Void(ctx context.Context, void vo.VoidRequest) (*http.Response, error) {
-	return a.sendRequest(ctx, http.MethodPost, voids, &void)
+	return a.post(ctx, voids, &void)
 }
 
 func (a *Api) Refund(ctx context.Context, refund vo.RefundRequest) (*http.Response, error) {
-	return a.sendRequest(ctx, http.MethodPost, refunds, &refund)
+	return a.post(ctx, refunds, &refund)
 }
 
-func (a *Api) sendRequest(ctx context.Context, method, path string, request interface{}) (*http.Response, error) {
-
-	//if request == nil {
-	//
-	//}
-	body, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
+func (a *Api) send(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
 	}
 
-	r, err := http.NewRequestWithContext(ctx, method, a.baseUrl+path, bytes.NewReader(body))
+	r, err := http.NewRequestWithContext(ctx, method, a.baseUrl+path, reader)
 	if err != nil {
 		return nil, err
 	}
 
-	r.Header.Set("Authorization", a.auth)
+	if a.auth != nil {
+		if err = a.auth.Authenticate(r); err != nil {
+			return nil, &AuthError{Err: err}
+		}
+	}
 	r.Header.Set("Accept", "application/json")
+	if a.language != "" {
+		r.Header.Set("Accept-Language", string(a.language))
+	}
 
 	if method == http.MethodPost {
 		//r.Header.Set("Content-Type", "application/json; charset=UTF-8")
 		r.Header.Set("Content-Type", "application/json")
 	}
 
-	return a.client.Do(r)
-}
-
-// if request doesnt have "request" field
-func marshalRequest(request interface{}) (io.Reader, error) {
-	b, err := json.Marshal(struct {
-		Request interface{} `json:"request"`
-	}{request})
-	if err != nil {
-		return nil, err
+	resp, err := a.client.Do(r)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		if i, ok := a.auth.(Invalidator); ok {
+			i.Invalidate(ctx)
+		}
 	}
-	return bytes.NewReader(b), nil
+	return resp, err
 }
 
 func (a Api) GetUrl() string {
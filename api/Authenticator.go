@@ -0,0 +1,276 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Authenticator sets credentials of a gateway request, e.g. Authorization header.
+// Implementations must be safe for concurrent use
+type Authenticator interface {
+	Authenticate(r *http.Request) error
+}
+
+// Invalidator is implemented by authenticators caching credentials, e.g. RotatingAuth.
+// Api calls Invalidate with the request context when the gateway answers 401
+type Invalidator interface {
+	Invalidate(ctx context.Context)
+}
+
+var ErrAuthentication = errors.New("bepaid: authentication failed")
+
+// AuthError is returned without sending the request if Authenticator fails. errors.Is matches ErrAuthentication
+// and errors of Err
+type AuthError struct {
+	Err error
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrAuthentication, e.Err)
+}
+
+func (e *AuthError) Is(target error) bool {
+	return target == ErrAuthentication
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// AuthenticatorFunc adapts a function to Authenticator, e.g. fake authentication in tests
+type AuthenticatorFunc func(r *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(r *http.Request) error {
+	return f(r)
+}
+
+// Credentials of a shop for HTTP basic authentication
+type Credentials struct {
+	ShopId    string
+	SecretKey string
+}
+
+func (c Credentials) header() string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.ShopId+":"+c.SecretKey))
+}
+
+type basicAuth string
+
+func (a basicAuth) Authenticate(r *http.Request) error {
+	r.Header.Set("Authorization", string(a))
+	return nil
+}
+
+// BasicAuth authenticates every request with the same credentials. It is the Authenticator of NewApi
+func BasicAuth(shopId, secretKey string) Authenticator {
+	return basicAuth(Credentials{ShopId: shopId, SecretKey: secretKey}.header())
+}
+
+// CredentialsProvider returns the current credentials, e.g. from a secret manager
+type CredentialsProvider func(ctx context.Context) (Credentials, error)
+
+// FileCredentials reads shop id and secret key from files on every call, e.g. from mounted secrets.
+// Spaces and line breaks around values are ignored
+func FileCredentials(shopIdFile, secretKeyFile string) CredentialsProvider {
+	return func(context.Context) (Credentials, error) {
+		shopId, err := ioutil.ReadFile(shopIdFile)
+		if err != nil {
+			return Credentials{}, err
+		}
+		secretKey, err := ioutil.ReadFile(secretKeyFile)
+		if err != nil {
+			return Credentials{}, err
+		}
+		return Credentials{ShopId: strings.TrimSpace(string(shopId)), SecretKey: strings.TrimSpace(string(secretKey))}, nil
+	}
+}
+
+// RotatingAuth authenticates requests with credentials of provider, so secrets are rotated without restart:
+//
+//	a := api.NewApi(client, baseUrl, "", "").
+//		WithAuthenticator(api.NewRotatingAuth(api.FileCredentials("/run/secrets/shop_id", "/run/secrets/secret_key")))
+//
+// Credentials are cached for refresh interval. Provider is called by one request at a time without holding the lock,
+// other requests use the cached credentials meanwhile or wait for the first credentials.
+// If provider fails, it is not called again for retry interval and the cached credentials are used until provider recovers
+type RotatingAuth struct {
+	provider CredentialsProvider
+	refresh  time.Duration
+	retry    time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	header  string
+	fetched time.Time
+	// fetching is closed when the running provider call completes, nil if provider is not called
+	fetching chan struct{}
+	failed   time.Time
+	err      error
+}
+
+func NewRotatingAuth(provider CredentialsProvider) *RotatingAuth {
+	return &RotatingAuth{provider: provider, refresh: time.Minute, retry: 5 * time.Second, now: time.Now}
+}
+
+// WithRefreshInterval sets how long credentials are cached. Zero calls provider for every request. Default is 1 minute
+func (a *RotatingAuth) WithRefreshInterval(refresh time.Duration) *RotatingAuth {
+	a.refresh = refresh
+	return a
+}
+
+// WithRetryInterval sets how long provider is not called after it failed. Default is 5 seconds
+func (a *RotatingAuth) WithRetryInterval(retry time.Duration) *RotatingAuth {
+	a.retry = retry
+	return a
+}
+
+// Invalidate makes the next request call provider. Api calls it when the gateway answers 401
+func (a *RotatingAuth) Invalidate(context.Context) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.fetched = time.Time{}
+}
+
+func (a *RotatingAuth) Authenticate(r *http.Request) error {
+	header, err := a.current(r.Context())
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set("Authorization", header)
+	return nil
+}
+
+func (a *RotatingAuth) current(ctx context.Context) (string, error) {
+	a.mu.Lock()
+
+	for {
+		now := a.now()
+		switch {
+		case a.header != "" && !a.fetched.IsZero() && now.Sub(a.fetched) < a.refresh:
+			header := a.header
+			a.mu.Unlock()
+			return header, nil
+		case a.fetching == nil && (a.failed.IsZero() || now.Sub(a.failed) >= a.retry):
+			return a.fetch(ctx)
+		case a.header != "":
+			header := a.header
+			a.mu.Unlock()
+			return header, nil
+		case a.fetching == nil:
+			err := a.err
+			a.mu.Unlock()
+			return "", err
+		}
+
+		fetching := a.fetching
+		a.mu.Unlock()
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		a.mu.Lock()
+	}
+}
+
+// fetch is called with a.mu locked and unlocks it
+func (a *RotatingAuth) fetch(ctx context.Context) (string, error) {
+	fetching := make(chan struct{})
+	a.fetching = fetching
+	a.mu.Unlock()
+
+	c, err := a.provider(ctx)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.fetching = nil
+	close(fetching)
+
+	if err != nil {
+		a.failed, a.err = a.now(), err
+		if a.header == "" {
+			return "", err
+		}
+		return a.header, nil
+	}
+
+	a.header, a.fetched = c.header(), a.now()
+	a.failed, a.err = time.Time{}, nil
+	return a.header, nil
+}
+
+var ErrUnknownShop = errors.New("bepaid: unknown shop")
+
+type shopKey struct{}
+
+// WithShop selects the shop of requests made with ctx, see ShopAuth
+func WithShop(ctx context.Context, shopId string) context.Context {
+	return context.WithValue(ctx, shopKey{}, shopId)
+}
+
+// ShopFromContext returns the shop set by WithShop
+func ShopFromContext(ctx context.Context) (string, bool) {
+	shopId, ok := ctx.Value(shopKey{}).(string)
+	return shopId, ok
+}
+
+// ShopAuth selects Authenticator by the shop of the request context, so one Api serves several shops:
+//
+//	auth := api.NewShopAuth(map[string]api.Authenticator{"1": api.BasicAuth("1", key1), "2": api.BasicAuth("2", key2)})
+//	a := api.NewApi(client, baseUrl, "", "").WithAuthenticator(auth)
+//	resp, err := a.StatusByUid(api.WithShop(ctx, "2"), uid)
+type ShopAuth struct {
+	shops    map[string]Authenticator
+	fallback Authenticator
+}
+
+func NewShopAuth(shops map[string]Authenticator) *ShopAuth {
+	copied := make(map[string]Authenticator, len(shops))
+	for shopId, auth := range shops {
+		copied[shopId] = auth
+	}
+	return &ShopAuth{shops: copied}
+}
+
+// WithDefault is used for requests without shop in context. Without it such requests fail with ErrUnknownShop
+func (a *ShopAuth) WithDefault(fallback Authenticator) *ShopAuth {
+	a.fallback = fallback
+	return a
+}
+
+func (a *ShopAuth) Authenticate(r *http.Request) error {
+	shopId, ok := ShopFromContext(r.Context())
+	if !ok {
+		if a.fallback == nil {
+			return fmt.Errorf("%w: shop is not set in context", ErrUnknownShop)
+		}
+		return a.fallback.Authenticate(r)
+	}
+
+	auth, ok := a.shops[shopId]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownShop, shopId)
+	}
+	return auth.Authenticate(r)
+}
+
+// Invalidate invalidates Authenticator of the shop of ctx if it is Invalidator
+func (a *ShopAuth) Invalidate(ctx context.Context) {
+	auth := a.fallback
+	if shopId, ok := ShopFromContext(ctx); ok {
+		auth = a.shops[shopId]
+	}
+	if i, ok := auth.(Invalidator); ok {
+		i.Invalidate(ctx)
+	}
+}
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("bepaid: circuit breaker is open")
+
+// CircuitOpenError is returned without sending the request while the circuit of shop and operation is open
+type CircuitOpenError struct {
+	Shop      string
+	Operation string
+
+	// RetryAfter is the time until the circuit lets a probe request through
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%v: shop %s, %s, retry after %s", ErrCircuitOpen, e.Shop, e.Operation, e.RetryAfter)
+}
+
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+type BreakerState int
+
+const (
+	// BreakerClosed sends all requests
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen fails all requests fast with CircuitOpenError
+	BreakerOpen
+
+	// BreakerHalfOpen sends a limited number of probe requests, the circuit closes if all of them succeed
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("BreakerState(%d)", int(s))
+}
+
+// BreakerSettings of CircuitBreaker. Zero fields are replaced with defaults
+type BreakerSettings struct {
+	// FailureRatio of failed requests in Window that opens the circuit. Default is 0.5
+	FailureRatio float64
+
+	// MinRequests in Window before FailureRatio is checked. Default is 10
+	MinRequests int
+
+	// Window of counting requests, counts are reset when it ends. Default is 1 minute
+	Window time.Duration
+
+	// OpenTimeout is the time in open state before probe requests are allowed. Default is 30 seconds
+	OpenTimeout time.Duration
+
+	// Probes is the number of successful probe requests needed to close the circuit. Default is 1
+	Probes int
+}
+
+func (s BreakerSettings) withDefaults() BreakerSettings {
+	if s.FailureRatio <= 0 {
+		s.FailureRatio = 0.5
+	}
+	if s.MinRequests <= 0 {
+		s.MinRequests = 10
+	}
+	if s.Window <= 0 {
+		s.Window = time.Minute
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = 30 * time.Second
+	}
+	if s.Probes <= 0 {
+		s.Probes = 1
+	}
+	return s
+}
+
+// CircuitState is the state of a circuit of shop and operation
+type CircuitState struct {
+	Shop      string
+	Operation string
+	State     BreakerState
+	Requests  int
+	Failures  int
+}
+
+// CircuitBreaker keeps a circuit per shop and per operation. Transport errors and 5xx responses are failures,
+// 4xx responses are not. Timeouts are failures too, requests cancelled by the caller or failed by Authenticator are not counted.
+//
+// One CircuitBreaker can be shared by Api instances of several shops:
+//
+//	breaker := api.NewCircuitBreaker(api.BreakerSettings{})
+//	a := api.NewApi(client, baseUrl, shopId, secretKey).WithCircuitBreaker(breaker)
+type CircuitBreaker struct {
+	settings BreakerSettings
+	now      func() time.Time
+
+	mu       sync.Mutex
+	circuits map[circuitKey]*circuit
+}
+
+type circuitKey struct {
+	shop      string
+	operation string
+}
+
+type circuit struct {
+	state    BreakerState
+	since    time.Time
+	requests int
+	failures int
+
+	// generation changes on every transition, results of requests allowed in another generation are ignored
+	generation uint64
+
+	// probes in flight and succeeded in half-open state
+	probing   int
+	succeeded int
+}
+
+func NewCircuitBreaker(settings BreakerSettings) *CircuitBreaker {
+	return &CircuitBreaker{
+		settings: settings.withDefaults(),
+		now:      time.Now,
+		circuits: map[circuitKey]*circuit{},
+	}
+}
+
+// State of the circuit of shop and operation. Operation is the path of the endpoint, e.g. "/transactions/payments"
+func (b *CircuitBreaker) State(shop, operation string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[circuitKey{shop, operation}]
+	if !ok {
+		return BreakerClosed
+	}
+	return b.current(c)
+}
+
+// States returns states of all circuits ordered by shop and operation
+func (b *CircuitBreaker) States() []CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	states := make([]CircuitState, 0, len(b.circuits))
+	for k, c := range b.circuits {
+		states = append(states, CircuitState{
+			Shop:      k.shop,
+			Operation: k.operation,
+			State:     b.current(c),
+			Requests:  c.requests,
+			Failures:  c.failures,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Shop != states[j].Shop {
+			return states[i].Shop < states[j].Shop
+		}
+		return states[i].Operation < states[j].Operation
+	})
+	return states
+}
+
+// Healthy reports whether no circuit is open
+func (b *CircuitBreaker) Healthy() bool {
+	for _, s := range b.States() {
+		if s.State == BreakerOpen {
+			return false
+		}
+	}
+	return true
+}
+
+// do sends request through the circuit of shop and operation
+func (b *CircuitBreaker) do(ctx context.Context, shop, operation string, send func() (*http.Response, error)) (*http.Response, error) {
+	key := circuitKey{shop, operation}
+	generation, err := b.allow(key)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := send()
+
+	switch {
+	// cancelled requests say nothing about the gateway, but timeouts of a slow gateway are failures
+	case err != nil && (errors.Is(ctx.Err(), context.Canceled) || errors.Is(err, ErrAuthentication)):
+		b.release(key, generation)
+	case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+		b.report(key, generation, false)
+	default:
+		b.report(key, generation, true)
+	}
+
+	return resp, err
+}
+
+// allow returns generation of the circuit to report the result of the request with
+func (b *CircuitBreaker) allow(key circuitKey) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{state: BreakerClosed, since: b.now()}
+		b.circuits[key] = c
+	}
+
+	switch b.current(c) {
+	case BreakerOpen:
+		return 0, &CircuitOpenError{Shop: key.shop, Operation: key.operation, RetryAfter: c.since.Add(b.settings.OpenTimeout).Sub(b.now())}
+	case BreakerHalfOpen:
+		if c.state == BreakerOpen {
+			b.transition(c, BreakerHalfOpen)
+		}
+		if c.probing+c.succeeded >= b.settings.Probes {
+			return 0, &CircuitOpenError{Shop: key.shop, Operation: key.operation}
+		}
+		c.probing++
+	default:
+		if b.now().Sub(c.since) >= b.settings.Window {
+			b.transition(c, BreakerClosed)
+		}
+	}
+	return c.generation, nil
+}
+
+// report counts the result of a request allowed in generation. Results of previous generations are stale,
+// e.g. of a slow request sent before the circuit opened, and are ignored
+func (b *CircuitBreaker) report(key circuitKey, generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuits[key]
+	if c.generation != generation {
+		return
+	}
+
+	if c.state == BreakerHalfOpen {
+		c.probing--
+		if !success {
+			b.transition(c, BreakerOpen)
+			return
+		}
+		c.succeeded++
+		if c.succeeded >= b.settings.Probes {
+			b.transition(c, BreakerClosed)
+		}
+		return
+	}
+
+	if c.state != BreakerClosed {
+		return
+	}
+	c.requests++
+	if !success {
+		c.failures++
+	}
+	if c.requests >= b.settings.MinRequests && float64(c.failures) >= b.settings.FailureRatio*float64(c.requests) {
+		b.transition(c, BreakerOpen)
+	}
+}
+
+// release frees the probe slot of a request which is not counted
+func (b *CircuitBreaker) release(key circuitKey, generation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if c := b.circuits[key]; c.generation == generation && c.state == BreakerHalfOpen {
+		c.probing--
+	}
+}
+
+// current is the state of c at the moment, open circuit becomes half-open after OpenTimeout
+func (b *CircuitBreaker) current(c *circuit) BreakerState {
+	if c.state == BreakerOpen && b.now().Sub(c.since) >= b.settings.OpenTimeout {
+		return BreakerHalfOpen
+	}
+	return c.state
+}
+
+func (b *CircuitBreaker) transition(c *circuit, state BreakerState) {
+	*c = circuit{state: state, since: b.now(), generation: c.generation + 1}
+}
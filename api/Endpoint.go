@@ -0,0 +1,65 @@
+package api
+
+import (
+	"bepaid-sdk/api/contracts"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// post sends request as JSON body. Test mode and default language of a are applied to requests
+// implementing contracts.RequestSetTest and contracts.RequestSetLanguage, request must be a pointer
+func (a *Api) post(ctx context.Context, path string, request interface{}) (*http.Response, error) {
+	if r, ok := request.(contracts.RequestSetTest); ok && a.testMode {
+		r.SetTest(true)
+	}
+	if r, ok := request.(contracts.RequestSetLanguage); ok && a.language != "" {
+		r.SetDefaultLanguage(a.language)
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.do(ctx, path, http.MethodPost, path, body)
+}
+
+// get requests escaped id appended to prefix. Requests are retried according to RetryPolicy of a
+func (a *Api) get(ctx context.Context, prefix, id string) (*http.Response, error) {
+	return a.retry.do(ctx, func() (*http.Response, error) {
+		return a.do(ctx, prefix, http.MethodGet, prefix+url.PathEscape(id), nil)
+	})
+}
+
+// do sends one http request through CircuitBreaker of a if it is set and logs its result if logger is set.
+// operation identifies the circuit, it is the path without id. Bodies are never logged
+func (a *Api) do(ctx context.Context, operation, method, path string, body []byte) (*http.Response, error) {
+	start := time.Now()
+
+	var resp *http.Response
+	var err error
+	if a.breaker != nil {
+		shop, ok := ShopFromContext(ctx)
+		if !ok {
+			shop = a.shop
+		}
+		resp, err = a.breaker.do(ctx, shop, operation, func() (*http.Response, error) {
+			return a.send(ctx, method, path, body)
+		})
+	} else {
+		resp, err = a.send(ctx, method, path, body)
+	}
+
+	if a.logger != nil {
+		if err != nil {
+			a.logger.Printf("bepaid: %s %s failed after %s: %v", method, path, time.Since(start), err)
+		} else {
+			a.logger.Printf("bepaid: %s %s %d in %s", method, path, resp.StatusCode, time.Since(start))
+		}
+	}
+
+	return resp, err
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// PingTrackingId is looked up by Ping. No transaction is expected to have it
+const PingTrackingId = "bepaid-sdk-ping"
+
+var (
+	// ErrDNS means the host of base URL can't be resolved
+	ErrDNS = errors.New("bepaid: dns lookup failed")
+
+	// ErrTLS means the TLS handshake or certificate verification failed
+	ErrTLS = errors.New("bepaid: tls failed")
+
+	// ErrUnreachable means the gateway can't be connected, didn't answer in time or its circuit is open
+	ErrUnreachable = errors.New("bepaid: gateway unreachable")
+
+	// ErrUnauthorized means the gateway rejected shop id or secret key or Authenticator failed
+	ErrUnauthorized = errors.New("bepaid: unauthorized, check shop id and secret key")
+
+	// ErrGateway means the gateway answered with an unexpected status or body, e.g. 5xx or 404 page of a wrong base URL
+	ErrGateway = errors.New("bepaid: gateway error")
+)
+
+// PingError is returned by Ping, errors.Is matches Kind and errors of Err
+type PingError struct {
+	// Kind is one of ErrDNS, ErrTLS, ErrUnreachable, ErrUnauthorized, ErrGateway
+	Kind error
+
+	// StatusCode is zero if the gateway didn't answer
+	StatusCode int
+
+	Err error
+}
+
+func (e *PingError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+	case e.StatusCode != 0:
+		return fmt.Sprintf("%v: status code %d", e.Kind, e.StatusCode)
+	}
+	return e.Kind.Error()
+}
+
+func (e *PingError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *PingError) Unwrap() error {
+	return e.Err
+}
+
+// Ping checks base URL and credentials with an authenticated status request of PingTrackingId.
+// The request is not retried, it is logged and goes through circuit breaker like other requests.
+//
+// The gateway answers an unknown tracking_id with a list of transactions or with JSON 404 and an error message
+// in the response section, both mean success. Nil means the gateway accepted credentials, otherwise the error is *PingError
+func (a *Api) Ping(ctx context.Context) error {
+	resp, err := a.do(ctx, statusTrackingId, http.MethodGet, statusTrackingId+url.PathEscape(PingTrackingId), nil)
+	if err != nil {
+		return &PingError{Kind: transportErrorKind(err), Err: err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return &PingError{Kind: ErrUnauthorized, StatusCode: resp.StatusCode}
+	case resp.StatusCode != http.StatusNotFound && (resp.StatusCode < 200 || resp.StatusCode >= 300):
+		return &PingError{Kind: ErrGateway, StatusCode: resp.StatusCode}
+	}
+
+	// anything else than the gateway, e.g. a web server at a wrong base URL, hardly answers with transactions
+	// or with the error envelope of the gateway
+	var body struct {
+		Transactions *[]json.RawMessage `json:"transactions"`
+		Response     struct {
+			Message string `json:"message"`
+		} `json:"response"`
+	}
+	if !isJSONResponse(resp) || json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&body) != nil {
+		return &PingError{Kind: ErrGateway, StatusCode: resp.StatusCode, Err: errNotGateway}
+	}
+	if resp.StatusCode == http.StatusNotFound && body.Response.Message == "" ||
+		resp.StatusCode != http.StatusNotFound && body.Transactions == nil {
+		return &PingError{Kind: ErrGateway, StatusCode: resp.StatusCode, Err: errNotGateway}
+	}
+	return nil
+}
+
+var errNotGateway = errors.New("response is neither a list of transactions nor a gateway error")
+
+func transportErrorKind(err error) error {
+	var dnsErr *net.DNSError
+	var recordErr tls.RecordHeaderError
+	var opErr *net.OpError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+
+	switch {
+	case errors.Is(err, ErrAuthentication):
+		return ErrUnauthorized
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrUnreachable
+	case errors.As(err, &dnsErr):
+		return ErrDNS
+	// since Go 1.20 x509 errors are wrapped in tls.CertificateVerificationError, errors.As finds them in both cases
+	case errors.Is(err, ErrPinMismatch), errors.As(err, &recordErr), errors.As(err, &unknownAuthority),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return ErrTLS
+	case errors.As(err, &opErr) && opErr.Op == "remote error":
+		// alert of the gateway, e.g. unsupported protocol version or missing client certificate
+		return ErrTLS
+	}
+	return ErrUnreachable
+}
+
+func isJSONResponse(resp *http.Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// HealthHandler is http.Handler for readiness probes. It answers 200 if Ping succeeds and no circuit of
+// the circuit breaker is open, otherwise 503. The body is JSON:
+//
+//	{"status":"ok"}
+//	{"status":"unavailable","error":"bepaid: unauthorized, check shop id and secret key: status code 401"}
+type HealthHandler struct {
+	api     *Api
+	timeout time.Duration
+}
+
+func NewHealthHandler(a *Api) *HealthHandler {
+	return &HealthHandler{api: a, timeout: 5 * time.Second}
+}
+
+// WithTimeout limits the duration of Ping. Default is 5 seconds
+func (h *HealthHandler) WithTimeout(timeout time.Duration) *HealthHandler {
+	h.timeout = timeout
+	return h
+}
+
+type healthStatus struct {
+	Status   string         `json:"status"`
+	Error    string         `json:"error,omitempty"`
+	Circuits []circuitState `json:"circuits,omitempty"`
+}
+
+type circuitState struct {
+	Shop      string `json:"shop"`
+	Operation string `json:"operation"`
+	State     string `json:"state"`
+}
+
+func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	status := healthStatus{Status: "ok"}
+	code := http.StatusOK
+
+	if err := h.api.Ping(ctx); err != nil {
+		status.Status = "unavailable"
+		status.Error = err.Error()
+		code = http.StatusServiceUnavailable
+	}
+
+	if h.api.breaker != nil {
+		for _, s := range h.api.breaker.States() {
+			if s.State == BreakerClosed {
+				continue
+			}
+			status.Circuits = append(status.Circuits, circuitState{Shop: s.Shop, Operation: s.Operation, State: s.State.String()})
+			if s.State == BreakerOpen && code == http.StatusOK {
+				status.Status = "degraded"
+				code = http.StatusServiceUnavailable
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(status)
+}
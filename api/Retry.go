@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy of status requests. Zero value disables retries.
+//
+// Payments, authorizations, captures, voids and refunds are never retried,
+// because the gateway might have processed the request. Use idempotency.Executor for them
+type RetryPolicy struct {
+	// MaxAttempts including the first one
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the second attempt, it doubles after each attempt
+	InitialBackoff time.Duration
+
+	// MaxBackoff limits the delay. Zero means no limit
+	MaxBackoff time.Duration
+}
+
+// do retries send on transport errors and 5xx responses, open circuit is not retried
+func (p RetryPolicy) do(ctx context.Context, send func() (*http.Response, error)) (*http.Response, error) {
+	backoff := p.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		resp, err := send()
+		if attempt >= p.MaxAttempts || ctx.Err() != nil || errors.Is(err, ErrCircuitOpen) {
+			return resp, err
+		}
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			return resp, nil
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrPinMismatch = errors.New("bepaid: no pinned key in certificate chain")
+
+// Pin is SHA-256 of DER encoded SubjectPublicKeyInfo of a certificate, the same as in HPKP:
+//
+//	openssl x509 -in cert.pem -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64
+type Pin [sha256.Size]byte
+
+// ParsePin parses base64 encoded pin with optional "sha256/" prefix
+func ParsePin(s string) (Pin, error) {
+	s = strings.TrimPrefix(s, "sha256/")
+
+	var p Pin
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return p, fmt.Errorf("bepaid: invalid pin %q: %w", s, err)
+	}
+	if len(b) != len(p) {
+		return p, fmt.Errorf("bepaid: invalid pin %q: %d bytes instead of %d", s, len(b), len(p))
+	}
+	copy(p[:], b)
+	return p, nil
+}
+
+// PinOf returns pin of the certificate public key
+func PinOf(cert *x509.Certificate) Pin {
+	return sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+}
+
+func (p Pin) String() string {
+	return "sha256/" + base64.StdEncoding.EncodeToString(p[:])
+}
+
+// TransportSettings of NewTransport. Zero fields are replaced with defaults
+type TransportSettings struct {
+	// DialTimeout of TCP connection. Default is 10 seconds
+	DialTimeout time.Duration
+
+	// TLSHandshakeTimeout default is 10 seconds
+	TLSHandshakeTimeout time.Duration
+
+	// ResponseHeaderTimeout is the time to wait for response headers after the request is sent. Default is 60 seconds,
+	// the gateway may wait for card issuer that long
+	ResponseHeaderTimeout time.Duration
+
+	// IdleConnTimeout closes idle connections. Default is 90 seconds
+	IdleConnTimeout time.Duration
+
+	// MaxIdleConnsPerHost default is 16
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost limits connections to the gateway. Zero means no limit
+	MaxConnsPerHost int
+
+	// RootCAs verify the gateway certificate. Nil means system roots
+	RootCAs *x509.CertPool
+
+	// Pins of keys of the gateway certificate chain. If set, at least one key of the verified chain must be pinned.
+	// Pin both current and backup keys, otherwise rotation of the gateway certificate breaks all requests
+	Pins []Pin
+
+	// Certificates are presented to the gateway for mutual TLS
+	Certificates []tls.Certificate
+}
+
+// NewTransport returns http.Transport with TLS 1.2 or later, timeouts and connection pooling suitable for
+// the gateway. Unlike http.DefaultTransport it never waits forever for a stuck connection:
+//
+//	client := &http.Client{Transport: api.NewTransport(api.TransportSettings{}), Timeout: time.Minute}
+//	a := api.NewApi(client, baseUrl, shopId, secretKey)
+func NewTransport(settings TransportSettings) *http.Transport {
+	settings = settings.withDefaults()
+
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		RootCAs:      settings.RootCAs,
+		Certificates: settings.Certificates,
+	}
+	if len(settings.Pins) > 0 {
+		tlsConfig.VerifyConnection = verifyPins(settings.Pins)
+	}
+
+	dialer := &net.Dialer{Timeout: settings.DialTimeout, KeepAlive: 30 * time.Second}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   settings.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: settings.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+		IdleConnTimeout:       settings.IdleConnTimeout,
+		MaxIdleConns:          settings.MaxIdleConnsPerHost,
+		MaxIdleConnsPerHost:   settings.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       settings.MaxConnsPerHost,
+		ForceAttemptHTTP2:     true,
+	}
+}
+
+func (s TransportSettings) withDefaults() TransportSettings {
+	if s.DialTimeout <= 0 {
+		s.DialTimeout = 10 * time.Second
+	}
+	if s.TLSHandshakeTimeout <= 0 {
+		s.TLSHandshakeTimeout = 10 * time.Second
+	}
+	if s.ResponseHeaderTimeout <= 0 {
+		s.ResponseHeaderTimeout = 60 * time.Second
+	}
+	if s.IdleConnTimeout <= 0 {
+		s.IdleConnTimeout = 90 * time.Second
+	}
+	if s.MaxIdleConnsPerHost <= 0 {
+		s.MaxIdleConnsPerHost = 16
+	}
+	return s
+}
+
+// verifyPins runs after the usual verification, so only keys of verified chains are matched
+func verifyPins(pins []Pin) func(tls.ConnectionState) error {
+	pinned := make(map[Pin]struct{}, len(pins))
+	for _, p := range pins {
+		pinned[p] = struct{}{}
+	}
+
+	return func(cs tls.ConnectionState) error {
+		for _, chain := range cs.VerifiedChains {
+			for _, cert := range chain {
+				if _, ok := pinned[PinOf(cert)]; ok {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("%w: %s", ErrPinMismatch, cs.ServerName)
+	}
+}
@@ -1,15 +1,17 @@
 package api
 
 import (
+	"bepaid-sdk/api/cassette"
 	"bepaid-sdk/service/vo"
 	"bytes"
 	"context"
 	"encoding/json"
+	"flag"
 	"io"
-	"math/rand"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
-	"time"
 )
 
 type P = vo.PaymentRequest
@@ -28,10 +30,13 @@ var (
 	// Default Api used by all tests.
 	api = Api{client: testingClient}
 
-	// TODO remove secrets
-	api2 = NewApi(http.DefaultClient, "https://gateway.bepaid.by", "", "")
+	record = flag.Bool("record", false, "send requests to the gateway and record them to testdata/cassettes, "+
+		"BEPAID_SHOP_ID and BEPAID_SECRET_KEY environment variables are required")
 )
 
+// amount of authorizations in recorded flows
+const testAmount = int64(50)
+
 type customRoundTripper struct{}
 
 func (customRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
@@ -93,7 +98,7 @@ func TestApi_CapturesMarshalRequest(t *testing.T) {
 		er   string
 	}{
 		{"defaultValue", C{}, `{"request":{"parent_uid":"","amount":0}}`},
-		{"requestConstructor", *vo.NewCaptureRequest("id123", int64(63)), `{"request":{"parent_uid":"id123","amount":63}}`},
+		{"requestConstructor", *vo.NewCaptureRequest(63, "id123"), `{"request":{"parent_uid":"id123","amount":63}}`},
 	}
 
 	for _, tc := range tests {
@@ -155,6 +160,8 @@ func TestApi_RefundsMarshalRequest(t *testing.T) {
 }
 
 func TestApi_Payment(t *testing.T) {
+	api2 := gatewayApi(t)
+
 	cc := vo.NewCreditCard("4200000000000000", "123", "tim", "01", "2024")
 	r := vo.NewPaymentRequest(int64(100), "RUB", "it's description", "mytrackingid", true, *cc).WithDuplicateCheck(false)
 
@@ -182,6 +189,8 @@ func TestApi_Payment(t *testing.T) {
 }
 
 func TestApi_Authorization(t *testing.T) {
+	api2 := gatewayApi(t)
+
 	cc := vo.NewCreditCard("4200000000000000", "123", "tim", "01", "2024")
 	r := vo.NewAuthorizationRequest(int64(100), "RUB", "it's description", "mytrackingid", true, *cc).WithDuplicateCheck(false)
 
@@ -210,7 +219,9 @@ func TestApi_Authorization(t *testing.T) {
 }
 
 func TestApi_AuthorizationCapture(t *testing.T) {
-	amount := rand.New(rand.NewSource(time.Now().Unix())).Int63() % 100
+	api2 := gatewayApi(t)
+
+	amount := testAmount
 
 	cc := vo.NewCreditCard("4200000000000000", "123", "tim", "01", "2024")
 	r := vo.NewAuthorizationRequest(amount, "RUB", "it's description", "mytrackingid", true, *cc)
@@ -230,7 +241,7 @@ func TestApi_AuthorizationCapture(t *testing.T) {
 	teeReader := io.TeeReader(resp.Body, &buf)
 	uid := getUid(t, teeReader)
 
-	cr := vo.NewCaptureRequest(uid, amount)
+	cr := vo.NewCaptureRequest(int(amount), uid)
 
 	resp, err = api2.Capture(context.Background(), *cr)
 	if err != nil {
@@ -249,7 +260,9 @@ func TestApi_AuthorizationCapture(t *testing.T) {
 }
 
 func TestApi_AuthorizationVoid(t *testing.T) {
-	amount := rand.New(rand.NewSource(time.Now().Unix())).Int63() % 100
+	api2 := gatewayApi(t)
+
+	amount := testAmount
 
 	cc := vo.NewCreditCard("4200000000000000", "123", "tim", "01", "2024")
 	r := vo.NewAuthorizationRequest(amount, "RUB", "it's description", "mytrackingid", true, *cc)
@@ -288,7 +301,9 @@ func TestApi_AuthorizationVoid(t *testing.T) {
 }
 
 func TestApi_AuthorizationCaptureRefund(t *testing.T) {
-	amount := rand.New(rand.NewSource(time.Now().Unix())).Int63() % 100
+	api2 := gatewayApi(t)
+
+	amount := testAmount
 
 	cc := vo.NewCreditCard("4200000000000000", "123", "tim", "01", "2024")
 	r := vo.NewAuthorizationRequest(amount, "RUB", "it's description", "mytrackingid", true, *cc)
@@ -308,7 +323,7 @@ func TestApi_AuthorizationCaptureRefund(t *testing.T) {
 	teeReader := io.TeeReader(resp.Body, &buf)
 	uid := getUid(t, teeReader)
 
-	cr := vo.NewCaptureRequest(uid, amount)
+	cr := vo.NewCaptureRequest(int(amount), uid)
 
 	resp, err = api2.Capture(context.Background(), *cr)
 	if err != nil {
@@ -345,7 +360,9 @@ func TestApi_AuthorizationCaptureRefund(t *testing.T) {
 }
 
 func TestApi_PaymentRefund(t *testing.T) {
-	r := vo.NewCaptureRequest("151281134-8d2c74c539", int64(100)).WithDuplicateCheck(false)
+	api2 := gatewayApi(t)
+
+	r := vo.NewCaptureRequest(100, "151281134-8d2c74c539").WithDuplicateCheck(false)
 
 	resp, err := api2.Capture(context.Background(), *r)
 	if err != nil {
@@ -371,6 +388,31 @@ func TestApi_PaymentRefund(t *testing.T) {
 
 }
 
+// gatewayApi replays gateway exchanges of the test from testdata/cassettes.
+// With -record flag requests are sent to the gateway and the cassette is rewritten
+func gatewayApi(t *testing.T) *Api {
+	mode := cassette.ModeReplay
+	if *record {
+		mode = cassette.ModeRecord
+	}
+
+	c, err := cassette.New(filepath.Join("testdata", "cassettes", t.Name()+".json"), mode)
+	if err != nil {
+		t.Fatalf("cassette.New: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := c.Save(); err != nil {
+			t.Errorf("cassette.Save: %v", err)
+		}
+		if unused := c.Unused(); len(unused) > 0 {
+			t.Errorf("%d recorded requests were not sent", len(unused))
+		}
+	})
+
+	return NewApi(&http.Client{Transport: c}, "https://gateway.bepaid.by", os.Getenv("BEPAID_SHOP_ID"), os.Getenv("BEPAID_SECRET_KEY"))
+}
+
 func testMarshallRequest(t *testing.T, er string, startRequest func() (*http.Response, error)) {
 	// ignore response and error
 	go startRequest()
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func authorization(ctx context.Context, a *Api) (string, error) {
+	var header string
+	a.client = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		header = r.Header.Get("Authorization")
+		return statusResponse(http.StatusOK), nil
+	})}
+	_, err := a.StatusByUid(ctx, "1-a")
+	return header, err
+}
+
+func TestBasicAuth(t *testing.T) {
+	header, err := authorization(context.Background(), NewApi(nil, "https://gateway.test", "1", "k"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Basic MTpr", header)
+}
+
+func TestRotatingAuth_FileCredentials(t *testing.T) {
+	dir := t.TempDir()
+	shopIdFile, secretKeyFile := filepath.Join(dir, "shop_id"), filepath.Join(dir, "secret_key")
+	assert.NoError(t, os.WriteFile(shopIdFile, []byte("1\n"), 0600))
+	assert.NoError(t, os.WriteFile(secretKeyFile, []byte("k\n"), 0600))
+
+	now := time.Unix(0, 0)
+	auth := NewRotatingAuth(FileCredentials(shopIdFile, secretKeyFile)).WithRefreshInterval(time.Minute)
+	auth.now = func() time.Time { return now }
+	a := NewApi(nil, "https://gateway.test", "", "").WithAuthenticator(auth)
+	ctx := context.Background()
+
+	header, err := authorization(ctx, a)
+	assert.NoError(t, err)
+	assert.Equal(t, "Basic MTpr", header)
+
+	// rotated secret is used after refresh interval
+	assert.NoError(t, os.WriteFile(secretKeyFile, []byte("k2"), 0600))
+	header, _ = authorization(ctx, a)
+	assert.Equal(t, "Basic MTpr", header)
+
+	now = now.Add(time.Minute)
+	header, _ = authorization(ctx, a)
+	assert.Equal(t, "Basic MTprMg==", header)
+
+	// cached credentials are used while the file is unavailable
+	assert.NoError(t, os.Remove(secretKeyFile))
+	auth.Invalidate(ctx)
+	header, err = authorization(ctx, a)
+	assert.NoError(t, err)
+	assert.Equal(t, "Basic MTprMg==", header)
+}
+
+func TestRotatingAuth_ProviderError(t *testing.T) {
+	breaker := NewCircuitBreaker(BreakerSettings{MinRequests: 1})
+	providerErr := errors.New("vault is sealed")
+	a := NewApi(nil, "https://gateway.test", "1", "").
+		WithAuthenticator(NewRotatingAuth(func(context.Context) (Credentials, error) { return Credentials{}, providerErr })).
+		WithCircuitBreaker(breaker)
+
+	_, err := authorization(context.Background(), a)
+
+	assert.ErrorIs(t, err, ErrAuthentication)
+	assert.ErrorIs(t, err, providerErr)
+	assert.True(t, breaker.Healthy())
+	assert.Equal(t, BreakerClosed, breaker.State("1", statusUid))
+}
+
+func TestRotatingAuth_ProviderIsCalledOnceOutsideLock(t *testing.T) {
+	calls := int32(0)
+	release := make(chan struct{})
+	auth := NewRotatingAuth(func(context.Context) (Credentials, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return Credentials{ShopId: "1", SecretKey: "k"}, nil
+	})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	headers := make([]string, 10)
+	for i := range headers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r, _ := http.NewRequest(http.MethodGet, "https://gateway.test", nil)
+			assert.NoError(t, auth.Authenticate(r))
+			headers[i] = r.Header.Get("Authorization")
+		}(i)
+	}
+
+	// Invalidate doesn't wait for the running provider call
+	auth.Invalidate(ctx)
+	// requests without credentials wait for the first provider call and respect their context
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	r, _ := http.NewRequestWithContext(cancelled, http.MethodGet, "https://gateway.test", nil)
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.ErrorIs(t, auth.Authenticate(r), context.Canceled)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, h := range headers {
+		assert.Equal(t, "Basic MTpr", h)
+	}
+}
+
+func TestRotatingAuth_BacksOffAfterFailure(t *testing.T) {
+	calls := 0
+	providerErr := errors.New("vault is sealed")
+	now := time.Unix(0, 0)
+	auth := NewRotatingAuth(func(context.Context) (Credentials, error) {
+		calls++
+		if calls == 1 {
+			return Credentials{ShopId: "1", SecretKey: "k"}, nil
+		}
+		return Credentials{}, providerErr
+	}).WithRefreshInterval(0).WithRetryInterval(time.Second)
+	auth.now = func() time.Time { return now }
+
+	authenticate := func() (string, error) {
+		r, _ := http.NewRequest(http.MethodGet, "https://gateway.test", nil)
+		err := auth.Authenticate(r)
+		return r.Header.Get("Authorization"), err
+	}
+
+	for i := 0; i < 3; i++ {
+		header, err := authenticate()
+		assert.NoError(t, err)
+		assert.Equal(t, "Basic MTpr", header)
+	}
+	assert.Equal(t, 2, calls)
+
+	now = now.Add(time.Second)
+	_, err := authenticate()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	failing := NewRotatingAuth(func(context.Context) (Credentials, error) {
+		calls++
+		return Credentials{}, providerErr
+	})
+	failing.now = auth.now
+	calls = 0
+	for i := 0; i < 3; i++ {
+		r, _ := http.NewRequest(http.MethodGet, "https://gateway.test", nil)
+		assert.ErrorIs(t, failing.Authenticate(r), providerErr)
+	}
+	assert.Equal(t, 1, calls)
+}
+
+func TestApi_UnauthorizedInvalidatesCredentials(t *testing.T) {
+	calls := 0
+	auth := NewRotatingAuth(func(context.Context) (Credentials, error) {
+		calls++
+		return Credentials{ShopId: "1", SecretKey: "k"}, nil
+	})
+	status := http.StatusUnauthorized
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return statusResponse(status), nil
+	})}
+	shops := NewShopAuth(map[string]Authenticator{"1": auth})
+	a := NewApi(client, "https://gateway.test", "", "").WithAuthenticator(shops)
+	ctx := WithShop(context.Background(), "1")
+
+	_, err := a.StatusByUid(ctx, "1-a")
+	assert.NoError(t, err)
+	_, err = a.StatusByUid(ctx, "1-a")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	status = http.StatusOK
+	_, err = a.StatusByUid(ctx, "1-a")
+	assert.NoError(t, err)
+	_, err = a.StatusByUid(ctx, "1-a")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestShopAuth(t *testing.T) {
+	breaker := NewCircuitBreaker(BreakerSettings{})
+	auth := NewShopAuth(map[string]Authenticator{
+		"1": BasicAuth("1", "k"),
+		"2": AuthenticatorFunc(func(r *http.Request) error {
+			r.Header.Set("Authorization", "fake")
+			return nil
+		}),
+	})
+	a := NewApi(nil, "https://gateway.test", "", "").WithAuthenticator(auth).WithCircuitBreaker(breaker)
+	ctx := context.Background()
+
+	header, err := authorization(WithShop(ctx, "2"), a)
+	assert.NoError(t, err)
+	assert.Equal(t, "fake", header)
+	assert.Equal(t, "2", breaker.States()[0].Shop)
+
+	_, err = authorization(WithShop(ctx, "3"), a)
+	assert.ErrorIs(t, err, ErrUnknownShop)
+
+	_, err = authorization(ctx, a)
+	assert.ErrorIs(t, err, ErrUnknownShop)
+
+	auth.WithDefault(BasicAuth("1", "k"))
+	header, err = authorization(ctx, a)
+	assert.NoError(t, err)
+	assert.Equal(t, "Basic MTpr", header)
+}
@@ -0,0 +1,257 @@
+package cassette
+
+import (
+	"bepaid-sdk/redact"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette file
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests to the real transport and saves exchanges to the cassette file on Save
+	ModeRecord
+)
+
+var ErrUnrecorded = errors.New("cassette: request is not recorded")
+
+// Interaction is one recorded exchange. Request headers are never recorded
+type Interaction struct {
+	Request struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+		Body   string `json:"body"`
+	} `json:"request"`
+
+	Response struct {
+		StatusCode  int    `json:"status_code"`
+		ContentType string `json:"content_type"`
+		Body        string `json:"body"`
+	} `json:"response"`
+}
+
+type cassette struct {
+	// Comment is for humans, e.g. origin of hand-written interactions. Recording doesn't keep it
+	Comment      string        `json:"comment,omitempty"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Transport is http.RoundTripper which records gateway exchanges to a file and replays them.
+//
+// Each recorded interaction is replayed once, in the order of recording,
+// so the same request may get different responses
+type Transport struct {
+	path      string
+	mode      Mode
+	strict    bool
+	transport http.RoundTripper
+	matchers  []Matcher
+	redactor  *redact.Redactor
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New loads cassette file in ModeReplay. In ModeRecord file is created on Save.
+//
+// By default requests are matched by method, path and body, unmatched requests fail with ErrUnrecorded
+// and redact.Default is applied to bodies
+func New(path string, mode Mode) (*Transport, error) {
+	t := &Transport{
+		path:      path,
+		mode:      mode,
+		strict:    true,
+		transport: http.DefaultTransport,
+		matchers:  []Matcher{MatchMethod, MatchPath, MatchBody},
+		redactor:  redact.Default(),
+	}
+
+	if mode == ModeRecord {
+		return t, nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c cassette
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("cassette %s: %w", path, err)
+	}
+	t.interactions = c.Interactions
+	t.used = make([]bool, len(c.Interactions))
+
+	return t, nil
+}
+
+// WithStrict(false) sends unmatched requests to the real transport in ModeReplay. Default is true
+func (t *Transport) WithStrict(strict bool) *Transport {
+	t.strict = strict
+	return t
+}
+
+// WithTransport sets the real transport. Default is http.DefaultTransport
+func (t *Transport) WithTransport(transport http.RoundTripper) *Transport {
+	t.transport = transport
+	return t
+}
+
+// WithMatchers replaces matchers. Request matches interaction if all matchers return true
+func (t *Transport) WithMatchers(matchers ...Matcher) *Transport {
+	t.matchers = matchers
+	return t
+}
+
+// WithRedactor replaces redact.Default. Request bodies are redacted before matching,
+// so replay works with real card data in tests
+func (t *Transport) WithRedactor(redactor *redact.Redactor) *Transport {
+	t.redactor = redactor
+	return t
+}
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	body = t.redactor.RedactString(body)
+
+	if t.mode == ModeRecord {
+		return t.record(r, body)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, in := range t.interactions {
+		if !t.used[i] && t.match(r, body, in) {
+			t.used[i] = true
+			return response(r, in), nil
+		}
+	}
+
+	if t.strict {
+		return nil, fmt.Errorf("%w: %s %s %s", ErrUnrecorded, r.Method, r.URL.Path, body)
+	}
+	return t.transport.RoundTrip(r)
+}
+
+// Save writes recorded interactions to the cassette file. It does nothing in ModeReplay
+func (t *Transport) Save() error {
+	if t.mode != ModeRecord {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, err := json.MarshalIndent(cassette{Interactions: t.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(t.path, append(b, '\n'), 0644)
+}
+
+// Unused returns interactions which were not replayed, useful to check that the test made all recorded requests
+func (t *Transport) Unused() []Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var result []Interaction
+	for i, in := range t.interactions {
+		if !t.used[i] {
+			result = append(result, in)
+		}
+	}
+	return result
+}
+
+func (t *Transport) record(r *http.Request, body string) (*http.Response, error) {
+	resp, err := t.transport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var in Interaction
+	in.Request.Method = r.Method
+	in.Request.Path = r.URL.Path
+	in.Request.Body = body
+	in.Response.StatusCode = resp.StatusCode
+	in.Response.ContentType = resp.Header.Get("Content-Type")
+	in.Response.Body = t.redactor.RedactString(string(b))
+
+	t.mu.Lock()
+	t.interactions = append(t.interactions, in)
+	t.used = append(t.used, true)
+	t.mu.Unlock()
+
+	return response(r, in), nil
+}
+
+func (t *Transport) match(r *http.Request, body string, in Interaction) bool {
+	for _, m := range t.matchers {
+		if !m(r, body, in) {
+			return false
+		}
+	}
+	return true
+}
+
+// readBody reads request body and replaces it, so the real transport can send it
+func readBody(r *http.Request) (string, error) {
+	if r.Body == nil {
+		return "", nil
+	}
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	if err = r.Body.Close(); err != nil {
+		return "", err
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return string(b), nil
+}
+
+func response(r *http.Request, in Interaction) *http.Response {
+	header := http.Header{}
+	if in.Response.ContentType != "" {
+		header.Set("Content-Type", in.Response.ContentType)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+		StatusCode:    in.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(in.Response.Body))),
+		ContentLength: int64(len(in.Response.Body)),
+		Request:       r,
+	}
+}
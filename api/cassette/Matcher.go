@@ -0,0 +1,31 @@
+package cassette
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+)
+
+// Matcher reports whether request r with redacted body matches recorded interaction
+type Matcher func(r *http.Request, body string, in Interaction) bool
+
+func MatchMethod(r *http.Request, _ string, in Interaction) bool {
+	return r.Method == in.Request.Method
+}
+
+func MatchPath(r *http.Request, _ string, in Interaction) bool {
+	return r.URL.Path == in.Request.Path
+}
+
+// MatchBody compares JSON bodies ignoring formatting and key order, other bodies are compared as strings
+func MatchBody(_ *http.Request, body string, in Interaction) bool {
+	if body == in.Request.Body {
+		return true
+	}
+
+	var a, b interface{}
+	if json.Unmarshal([]byte(body), &a) != nil || json.Unmarshal([]byte(in.Request.Body), &b) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,129 @@
+package cassette
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func post(t *testing.T, c *http.Client, url, body string) (*http.Response, string, error) {
+	resp, err := c.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(b), nil
+}
+
+func TestTransport_RecordReplay(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"transaction":{"uid":"%d","credit_card":{"token":"secret-token"}}}`, n)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	body := `{"request":{"amount":1,"credit_card":{"number":"4200000000000000"}}}`
+
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: rec}
+	for i := 0; i < 2; i++ {
+		_, b, err := post(t, client, server.URL+"/transactions/payments", body)
+		assert.Nil(t, err)
+		assert.Equal(t, fmt.Sprintf(`{"transaction":{"credit_card":{"token":"[REDACTED]"},"uid":"%d"}}`, i+1), b)
+	}
+	assert.Nil(t, rec.Save())
+
+	b, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(b), "4200000000000000")
+	assert.NotContains(t, string(b), "secret-token")
+
+	replay, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: replay}
+
+	// same request is answered in the order of recording, JSON formatting doesn't matter
+	for i := 0; i < 2; i++ {
+		resp, b, err := post(t, client, "https://gateway.bepaid.by/transactions/payments", `{"request": {"credit_card": {"number": "5555"}, "amount": 1}}`)
+		assert.Nil(t, err)
+		assert.Equal(t, 200, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Contains(t, b, fmt.Sprintf(`"uid":"%d"`, i+1))
+	}
+	assert.Empty(t, replay.Unused())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	_, _, err = post(t, client, "https://gateway.bepaid.by/transactions/payments", body)
+	assert.True(t, errors.Is(err, ErrUnrecorded), "unexpected error: %v", err)
+}
+
+func TestTransport_ReplayMatchers(t *testing.T) {
+	path := filepath.Join("testdata", "payments.json")
+
+	tests := []struct {
+		name     string
+		matchers []Matcher
+		path     string
+		body     string
+		err      bool
+	}{
+		{"defaultMatchers", nil, "/transactions/payments", `{"amount":1}`, false},
+		{"bodyDiffers", nil, "/transactions/payments", `{"amount":2}`, true},
+		{"pathDiffers", nil, "/transactions/refunds", `{"amount":1}`, true},
+		{"ignoreBody", []Matcher{MatchMethod, MatchPath}, "/transactions/payments", `{"amount":2}`, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tr, err := New(path, ModeReplay)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.matchers != nil {
+				tr.WithMatchers(tc.matchers...)
+			}
+
+			_, _, err = post(t, &http.Client{Transport: tr}, "https://gateway.bepaid.by"+tc.path, tc.body)
+			assert.Equal(t, tc.err, err != nil, "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestTransport_NotStrict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "live")
+	}))
+	defer server.Close()
+
+	tr, err := New(filepath.Join("testdata", "payments.json"), ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.WithStrict(false)
+
+	_, b, err := post(t, &http.Client{Transport: tr}, server.URL+"/transactions/refunds", `{}`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "live", b)
+}
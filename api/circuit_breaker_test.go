@@ -0,0 +1,193 @@
+package api
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func breakerApi(breaker *CircuitBreaker, status *int, calls *int) *Api {
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		*calls++
+		if *status == 0 {
+			return nil, errors.New("connection refused")
+		}
+		return statusResponse(*status), nil
+	})}
+	return NewApi(client, "https://gateway.test", "shop-1", "k").WithCircuitBreaker(breaker)
+}
+
+func TestCircuitBreaker_OpensAndHalfOpens(t *testing.T) {
+	c := &clock{t: time.Unix(0, 0)}
+	breaker := NewCircuitBreaker(BreakerSettings{FailureRatio: 0.5, MinRequests: 4, OpenTimeout: 10 * time.Second})
+	breaker.now = c.now
+
+	status, calls := http.StatusOK, 0
+	a := breakerApi(breaker, &status, &calls)
+	ctx := context.Background()
+
+	_, err := a.StatusByUid(ctx, "1-a")
+	assert.NoError(t, err)
+	_, err = a.StatusByUid(ctx, "1-b")
+	assert.NoError(t, err)
+
+	status = http.StatusBadGateway
+	_, err = a.StatusByUid(ctx, "1-c")
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, breaker.State("shop-1", statusUid))
+
+	status = 0
+	_, err = a.StatusByUid(ctx, "1-d")
+	assert.Error(t, err)
+	assert.Equal(t, BreakerOpen, breaker.State("shop-1", statusUid))
+	assert.False(t, breaker.Healthy())
+
+	_, err = a.StatusByUid(ctx, "1-e")
+	var openErr *CircuitOpenError
+	assert.ErrorAs(t, err, &openErr)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 10*time.Second, openErr.RetryAfter)
+	assert.Equal(t, 4, calls)
+
+	// other operations have their own circuits
+	_, err = a.StatusByTrackingId(ctx, "order-1")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+
+	c.t = c.t.Add(10 * time.Second)
+	assert.Equal(t, BreakerHalfOpen, breaker.State("shop-1", statusUid))
+
+	// failed probe opens the circuit again
+	_, err = a.StatusByUid(ctx, "1-f")
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, BreakerOpen, breaker.State("shop-1", statusUid))
+
+	c.t = c.t.Add(10 * time.Second)
+	status = http.StatusNotFound
+	_, err = a.StatusByUid(ctx, "1-g")
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, breaker.State("shop-1", statusUid))
+	assert.True(t, breaker.Healthy())
+}
+
+func TestCircuitBreaker_PerShop(t *testing.T) {
+	breaker := NewCircuitBreaker(BreakerSettings{MinRequests: 1})
+
+	status, calls := http.StatusServiceUnavailable, 0
+	a := breakerApi(breaker, &status, &calls)
+	_, err := a.Refund(context.Background(), *vo.NewRefundRequest("1-a", 100, "return"))
+	assert.NoError(t, err)
+
+	okStatus, okCalls := http.StatusOK, 0
+	b := NewApi(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		okCalls++
+		return statusResponse(okStatus), nil
+	})}, "https://gateway.test", "shop-2", "k").WithCircuitBreaker(breaker)
+	_, err = b.Refund(context.Background(), *vo.NewRefundRequest("1-a", 100, "return"))
+	assert.NoError(t, err)
+
+	_, err = a.Refund(context.Background(), *vo.NewRefundRequest("1-a", 100, "return"))
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	assert.Equal(t, []CircuitState{
+		{Shop: "shop-1", Operation: refunds, State: BreakerOpen},
+		{Shop: "shop-2", Operation: refunds, State: BreakerClosed, Requests: 1},
+	}, breaker.States())
+}
+
+func TestCircuitBreaker_OpenCircuitIsNotRetried(t *testing.T) {
+	breaker := NewCircuitBreaker(BreakerSettings{MinRequests: 1})
+
+	status, calls := 0, 0
+	a := breakerApi(breaker, &status, &calls).
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond})
+
+	_, err := a.StatusByTrackingId(context.Background(), "order-1")
+
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 1, calls)
+}
+
+func TestCircuitBreaker_CancelledRequestIsNotFailure(t *testing.T) {
+	breaker := NewCircuitBreaker(BreakerSettings{MinRequests: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, r.Context().Err()
+	})}
+	a := NewApi(client, "https://gateway.test", "shop-1", "k").WithCircuitBreaker(breaker)
+
+	_, err := a.StatusByUid(ctx, "1-a")
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, BreakerClosed, breaker.State("shop-1", statusUid))
+}
+
+func TestCircuitBreaker_TimeoutIsFailure(t *testing.T) {
+	breaker := NewCircuitBreaker(BreakerSettings{MinRequests: 1})
+
+	// the gateway answers after the deadline of the caller
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		select {
+		case <-r.Context().Done():
+			return nil, r.Context().Err()
+		case <-time.After(time.Second):
+			return statusResponse(http.StatusOK), nil
+		}
+	})}
+	a := NewApi(client, "https://gateway.test", "shop-1", "k").WithCircuitBreaker(breaker)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := a.Void(ctx, *vo.NewVoidRequest("1-a", 100))
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, BreakerOpen, breaker.State("shop-1", voids))
+
+	_, err = a.Void(context.Background(), *vo.NewVoidRequest("1-a", 100))
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestCircuitBreaker_StaleReportsAreIgnored(t *testing.T) {
+	c := &clock{t: time.Unix(0, 0)}
+	breaker := NewCircuitBreaker(BreakerSettings{FailureRatio: 0.5, MinRequests: 1, OpenTimeout: 10 * time.Second, Probes: 1})
+	breaker.now = c.now
+	key := circuitKey{"shop-1", statusUid}
+
+	// slow request started while the circuit is closed
+	slow, err := breaker.allow(key)
+	assert.NoError(t, err)
+
+	failed, _ := breaker.allow(key)
+	breaker.report(key, failed, false)
+	assert.Equal(t, BreakerOpen, breaker.State("shop-1", statusUid))
+
+	c.t = c.t.Add(10 * time.Second)
+	probe, err := breaker.allow(key)
+	assert.NoError(t, err)
+
+	// result of the slow request is neither a probe nor frees the probe slot
+	breaker.report(key, slow, true)
+	breaker.release(key, slow)
+	assert.Equal(t, BreakerHalfOpen, breaker.State("shop-1", statusUid))
+	_, err = breaker.allow(key)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	breaker.report(key, probe, true)
+	assert.Equal(t, BreakerClosed, breaker.State("shop-1", statusUid))
+	assert.Equal(t, 0, breaker.circuits[key].probing)
+}
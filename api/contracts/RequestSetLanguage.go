@@ -0,0 +1,11 @@
+package contracts
+
+import "bepaid-sdk/service/vo"
+
+// RequestSetLanguage wraps SetDefaultLanguage method
+//
+// Api implementation should let user set a default language and call SetDefaultLanguage on every request
+// implementing this interface. Language set on the request itself has priority
+type RequestSetLanguage interface {
+	SetDefaultLanguage(language vo.Language)
+}
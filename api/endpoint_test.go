@@ -0,0 +1,60 @@
+package api
+
+import (
+	"bepaid-sdk/service/vo"
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApi_PostSendsJSON(t *testing.T) {
+	var method, path, body string
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		method, path = r.Method, r.URL.EscapedPath()
+		b := new(bytes.Buffer)
+		b.ReadFrom(r.Body)
+		body = b.String()
+		return statusResponse(http.StatusOK), nil
+	})}
+	a := NewApi(client, "https://gateway.test", "1", "k")
+
+	_, err := a.Refund(context.Background(), *vo.NewRefundRequest("1-a", 5, "reason"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/transactions/refunds", path)
+	assert.JSONEq(t, `{"request":{"parent_uid":"1-a","amount":5,"reason":"reason"}}`, body)
+}
+
+func TestApi_GetEscapesId(t *testing.T) {
+	var path string
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		path = r.URL.EscapedPath()
+		assert.Nil(t, r.Body)
+		return statusResponse(http.StatusOK), nil
+	})}
+	a := NewApi(client, "https://gateway.test", "1", "k")
+
+	_, err := a.StatusByTrackingId(context.Background(), "order 1/2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/v2/transactions/tracking_id/order%201%2F2", path)
+}
+
+func TestApi_WithLogger(t *testing.T) {
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return statusResponse(http.StatusCreated), nil
+	})}
+	out := new(bytes.Buffer)
+	a := NewApi(client, "https://gateway.test", "1", "secret").WithLogger(log.New(out, "", 0))
+
+	_, err := a.StatusByUid(context.Background(), "1-a")
+
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "bepaid: GET /transactions/1-a 201 in ")
+	assert.NotContains(t, out.String(), "secret")
+}
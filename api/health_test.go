@@ -0,0 +1,161 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gateway(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	s := httptest.NewServer(handler)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestApi_Ping(t *testing.T) {
+	s := gateway(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, statusTrackingId+PingTrackingId, r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch user, _, _ := r.BasicAuth(); user {
+		case "1":
+			w.Write([]byte(`{"transactions":[]}`))
+		case "3":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"response":{"message":"Transaction not found"}}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+
+	out := new(bytes.Buffer)
+	assert.NoError(t, NewApi(s.Client(), s.URL, "1", "k").WithLogger(log.New(out, "", 0)).Ping(context.Background()))
+	assert.Contains(t, out.String(), "bepaid: GET "+statusTrackingId+PingTrackingId+" 200")
+
+	// unknown tracking_id of an authenticated request
+	assert.NoError(t, NewApi(s.Client(), s.URL, "3", "k").Ping(context.Background()))
+
+	err := NewApi(s.Client(), s.URL, "2", "k").Ping(context.Background())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	var pingErr *PingError
+	assert.ErrorAs(t, err, &pingErr)
+	assert.Equal(t, http.StatusUnauthorized, pingErr.StatusCode)
+}
+
+func TestApi_PingGatewayErrors(t *testing.T) {
+	s := gateway(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json404" + statusTrackingId + PingTrackingId:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not found"}`))
+		case "/html404" + statusTrackingId + PingTrackingId:
+			http.NotFound(w, r)
+		case "/json200" + statusTrackingId + PingTrackingId:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"status":"ok"}`))
+		case "/html" + statusTrackingId + PingTrackingId:
+			w.Write([]byte(`<html></html>`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	})
+
+	for _, path := range []string{"", "/json404", "/html404", "/json200", "/html"} {
+		assert.ErrorIs(t, NewApi(s.Client(), s.URL+path, "1", "k").Ping(context.Background()), ErrGateway, path)
+	}
+}
+
+func TestApi_PingGoesThroughCircuitBreaker(t *testing.T) {
+	calls := 0
+	s := gateway(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	a := NewApi(s.Client(), s.URL, "1", "k").WithCircuitBreaker(NewCircuitBreaker(BreakerSettings{MinRequests: 1}))
+
+	assert.ErrorIs(t, a.Ping(context.Background()), ErrGateway)
+	err := a.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrUnreachable)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 1, calls)
+}
+
+func TestApi_PingTransportErrors(t *testing.T) {
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+
+	// client doesn't trust the test certificate
+	err := NewApi(&http.Client{}, tlsServer.URL, "1", "k").Ping(context.Background())
+	assert.ErrorIs(t, err, ErrTLS)
+
+	dns := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, &net.DNSError{Err: "no such host", Name: "gateway.test", IsNotFound: true}
+	})}
+	err = NewApi(dns, "https://gateway.test", "1", "k").Ping(context.Background())
+	assert.ErrorIs(t, err, ErrDNS)
+	var dnsErr *net.DNSError
+	assert.ErrorAs(t, err, &dnsErr)
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	err = NewApi(&http.Client{}, closed.URL, "1", "k").Ping(context.Background())
+	assert.ErrorIs(t, err, ErrUnreachable)
+}
+
+func TestHealthHandler(t *testing.T) {
+	status := http.StatusOK
+	s := gateway(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"transactions":[]}`))
+	})
+	breaker := NewCircuitBreaker(BreakerSettings{MinRequests: 1})
+	a := NewApi(s.Client(), s.URL, "1", "k").WithCircuitBreaker(breaker)
+	h := NewHealthHandler(a)
+
+	serve := func() (int, healthStatus) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		var body healthStatus
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return rec.Code, body
+	}
+
+	code, body := serve()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body.Status)
+
+	status = http.StatusServiceUnavailable
+	_, err := a.StatusByUid(context.Background(), "1-a")
+	assert.NoError(t, err)
+
+	status = http.StatusOK
+	code, body = serve()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "degraded", body.Status)
+	assert.Empty(t, body.Error)
+	assert.Equal(t, []circuitState{{Shop: "1", Operation: statusUid, State: "open"}}, body.Circuits)
+
+	status = http.StatusServiceUnavailable
+	code, body = serve()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", body.Status)
+	assert.Equal(t, []circuitState{{Shop: "1", Operation: statusUid, State: "open"}}, body.Circuits)
+
+	// the second failed ping of four opens the ping circuit
+	code, body = serve()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unavailable", body.Status)
+	assert.Equal(t, []circuitState{
+		{Shop: "1", Operation: statusUid, State: "open"},
+		{Shop: "1", Operation: statusTrackingId, State: "open"},
+	}, body.Circuits)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApi_WithLanguage(t *testing.T) {
+	var header string
+	var body struct {
+		Request struct {
+			Language vo.Language `json:"language"`
+		} `json:"request"`
+	}
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		header = r.Header.Get("Accept-Language")
+		if r.Body != nil {
+			b, _ := ioutil.ReadAll(r.Body)
+			assert.NoError(t, json.Unmarshal(b, &body))
+		}
+		return statusResponse(http.StatusOK), nil
+	})}
+	a := NewApi(client, "https://gateway.test", "1", "k").WithLanguage(vo.LanguageBelarusian)
+	ctx := context.Background()
+
+	_, err := a.Payment(ctx, *vo.NewPaymentRequest(100, "BYN", "order", "order-1", false, vo.CreditCard{}))
+	assert.NoError(t, err)
+	assert.Equal(t, vo.LanguageBelarusian, body.Request.Language)
+	assert.Equal(t, "be", header)
+
+	_, err = a.Authorization(ctx, *vo.NewAuthorizationRequest(100, "BYN", "order", "order-1", false, vo.CreditCard{}).
+		WithLanguage(vo.LanguageEnglish))
+	assert.NoError(t, err)
+	assert.Equal(t, vo.LanguageEnglish, body.Request.Language)
+
+	_, err = a.StatusByUid(ctx, "1-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "be", header)
+}
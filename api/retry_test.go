@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func statusResponse(code int) *http.Response {
+	return &http.Response{StatusCode: code, Body: ioutil.NopCloser(strings.NewReader("{}"))}
+}
+
+func TestApi_RetriesStatusRequests(t *testing.T) {
+	calls := 0
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		switch calls {
+		case 1:
+			return nil, errors.New("connection reset")
+		case 2:
+			return statusResponse(http.StatusBadGateway), nil
+		}
+		return statusResponse(http.StatusOK), nil
+	})}
+
+	a := NewApi(client, "https://gateway.test", "1", "k").
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	resp, err := a.StatusByUid(context.Background(), "1-a")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, calls)
+}
+
+func TestApi_RetryGivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return statusResponse(http.StatusServiceUnavailable), nil
+	})}
+
+	a := NewApi(client, "https://gateway.test", "1", "k").
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+
+	resp, err := a.StatusByTrackingId(context.Background(), "order-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, 2, calls)
+}
+
+func TestApi_DoesNotRetryPost(t *testing.T) {
+	calls := 0
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		calls++
+		return statusResponse(http.StatusBadGateway), nil
+	})}
+
+	a := NewApi(client, "https://gateway.test", "1", "k").
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	_, err := a.Capture(context.Background(), *vo.NewCaptureRequest(10, "1-a"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestApi_TestMode(t *testing.T) {
+	var body string
+	client := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		return statusResponse(http.StatusOK), nil
+	})}
+
+	a := NewApi(client, "https://gateway.test", "1", "k").WithTestMode(true)
+
+	_, err := a.Payment(context.Background(), *vo.NewPaymentRequest(1, "BYN", "d", "t", false, vo.CreditCard{}))
+
+	assert.NoError(t, err)
+	assert.Contains(t, body, `"test":true`)
+}
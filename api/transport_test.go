@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tlsGateway answers 200 to requests over TLS, client certificates are requested if clientCAs is set
+func tlsGateway(t *testing.T, configure func(*tls.Config)) (*httptest.Server, *x509.CertPool) {
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"transactions":[]}`))
+	}))
+	s.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	s.TLS = &tls.Config{}
+	if configure != nil {
+		configure(s.TLS)
+	}
+	s.StartTLS()
+	t.Cleanup(s.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(s.Certificate())
+	return s, roots
+}
+
+func clientCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "shop-1"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func pingWith(url string, settings TransportSettings) error {
+	client := &http.Client{Transport: NewTransport(settings), Timeout: 5 * time.Second}
+	return NewApi(client, url, "1", "k").Ping(context.Background())
+}
+
+func TestNewTransport_Defaults(t *testing.T) {
+	transport := NewTransport(TransportSettings{})
+
+	assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+	assert.Equal(t, 10*time.Second, transport.TLSHandshakeTimeout)
+	assert.Equal(t, 60*time.Second, transport.ResponseHeaderTimeout)
+	assert.Equal(t, 90*time.Second, transport.IdleConnTimeout)
+	assert.Equal(t, 16, transport.MaxIdleConnsPerHost)
+	assert.Nil(t, transport.TLSClientConfig.VerifyConnection)
+}
+
+func TestNewTransport_RootCAs(t *testing.T) {
+	s, roots := tlsGateway(t, nil)
+
+	assert.ErrorIs(t, pingWith(s.URL, TransportSettings{}), ErrTLS)
+	assert.NoError(t, pingWith(s.URL, TransportSettings{RootCAs: roots}))
+}
+
+func TestNewTransport_RejectsOldTLS(t *testing.T) {
+	s, roots := tlsGateway(t, func(c *tls.Config) { c.MaxVersion = tls.VersionTLS11 })
+
+	assert.ErrorIs(t, pingWith(s.URL, TransportSettings{RootCAs: roots}), ErrTLS)
+}
+
+func TestNewTransport_Pins(t *testing.T) {
+	s, roots := tlsGateway(t, nil)
+
+	pin := PinOf(s.Certificate())
+	parsed, err := ParsePin(pin.String())
+	assert.NoError(t, err)
+	assert.Equal(t, pin, parsed)
+
+	other, err := ParsePin("sha256/AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+	assert.NoError(t, err)
+
+	assert.NoError(t, pingWith(s.URL, TransportSettings{RootCAs: roots, Pins: []Pin{other, pin}}))
+
+	err = pingWith(s.URL, TransportSettings{RootCAs: roots, Pins: []Pin{other}})
+	assert.ErrorIs(t, err, ErrPinMismatch)
+	assert.ErrorIs(t, err, ErrTLS)
+}
+
+func TestParsePin_Invalid(t *testing.T) {
+	_, err := ParsePin("sha256/not base64")
+	assert.Error(t, err)
+
+	_, err = ParsePin("AAAA")
+	assert.ErrorContains(t, err, "3 bytes instead of 32")
+}
+
+func TestNewTransport_ClientCertificate(t *testing.T) {
+	cert := clientCertificate(t)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(leaf)
+
+	s, roots := tlsGateway(t, func(c *tls.Config) {
+		c.ClientAuth = tls.RequireAndVerifyClientCert
+		c.ClientCAs = clientCAs
+	})
+
+	assert.ErrorIs(t, pingWith(s.URL, TransportSettings{RootCAs: roots}), ErrTLS)
+	assert.NoError(t, pingWith(s.URL, TransportSettings{RootCAs: roots, Certificates: []tls.Certificate{cert}}))
+}
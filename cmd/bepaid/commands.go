@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bepaid-sdk/config"
+	"bepaid-sdk/service/contracts"
+	"bepaid-sdk/service/vo"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	exitOk    = 0
+	exitError = 1
+	exitUsage = 2
+
+	// card number which is always successful in test mode
+	testCardNumber = "4200000000000000"
+)
+
+var errConfirmation = errors.New("money-moving command requires -yes flag")
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, s contracts.ApiService, args []string, out printer) error
+}
+
+var commands = map[string]command{
+	"status":       {"-uid UID | -tracking-id ID", status},
+	"capture":      {"-uid UID -amount N -yes", capture},
+	"void":         {"-uid UID -amount N -yes", void},
+	"refund":       {"-uid UID -amount N -reason TEXT -yes", refund},
+	"test-payment": {"-amount N -currency CUR [-tracking-id ID] [-card NUMBER]", testPayment},
+}
+
+func run(args []string, lookupEnv func(string) (string, bool), stdout, stderr io.Writer, newService func(config.Config) contracts.ApiService) int {
+	fs := flag.NewFlagSet("bepaid", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configFile := fs.String("config", "", "YAML or JSON config file, default is $BEPAID_CONFIG")
+	format := fs.String("o", "table", "output format: table or json")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: bepaid [-config file] [-o table|json] <command> [flags]")
+		for _, name := range []string{"status", "capture", "void", "refund", "test-payment"} {
+			fmt.Fprintf(stderr, "  %-13s %s\n", name, commands[name].usage)
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n", fs.Arg(0))
+		fs.Usage()
+		return exitUsage
+	}
+
+	out, err := newPrinter(*format, stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	c, err := config.LoadWith(*configFile, lookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	err = cmd.run(context.Background(), newService(c), fs.Args()[1:], out)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitUsage
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		if errors.Is(err, errConfirmation) || errors.Is(err, errUsage) {
+			return exitUsage
+		}
+		return exitError
+	}
+
+	return exitOk
+}
+
+var errUsage = errors.New("invalid arguments")
+
+func status(ctx context.Context, s contracts.ApiService, args []string, out printer) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	uid := fs.String("uid", "", "transaction uid")
+	trackingId := fs.String("tracking-id", "", "tracking id of transactions")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch {
+	case *uid != "" && *trackingId == "":
+		tr, err := s.StatusByUid(ctx, *uid)
+		if err != nil {
+			return err
+		}
+		return out.transaction(tr)
+	case *trackingId != "" && *uid == "":
+		tr, err := s.StatusByTrackingId(ctx, *trackingId)
+		if err != nil {
+			return err
+		}
+		return out.transactions(tr)
+	}
+
+	return fmt.Errorf("%w: exactly one of -uid and -tracking-id is required", errUsage)
+}
+
+// moneyFlags are flags shared by capture, void and refund
+type moneyFlags struct {
+	fs     *flag.FlagSet
+	uid    *string
+	amount *int64
+	yes    *bool
+}
+
+func newMoneyFlags(name string) moneyFlags {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	return moneyFlags{
+		fs:     fs,
+		uid:    fs.String("uid", "", "parent transaction uid"),
+		amount: fs.Int64("amount", 0, "amount in minimal currency units, e.g. 1000 for 10.00"),
+		yes:    fs.Bool("yes", false, "confirm the operation"),
+	}
+}
+
+func (m moneyFlags) parse(args []string) error {
+	if err := m.fs.Parse(args); err != nil {
+		return err
+	}
+	if *m.uid == "" || *m.amount <= 0 {
+		return fmt.Errorf("%w: -uid and positive -amount are required", errUsage)
+	}
+	if !*m.yes {
+		return fmt.Errorf("%s %d of %s: %w", m.fs.Name(), *m.amount, *m.uid, errConfirmation)
+	}
+	return nil
+}
+
+func capture(ctx context.Context, s contracts.ApiService, args []string, out printer) error {
+	f := newMoneyFlags("capture")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+
+	r := vo.CaptureRequest{}
+	r.Request.ParentUid = *f.uid
+	r.Request.Amount = *f.amount
+
+	tr, err := s.Capture(ctx, r)
+	if err != nil {
+		return err
+	}
+	return out.transaction(tr)
+}
+
+func void(ctx context.Context, s contracts.ApiService, args []string, out printer) error {
+	f := newMoneyFlags("void")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+
+	tr, err := s.Void(ctx, *vo.NewVoidRequest(*f.uid, *f.amount))
+	if err != nil {
+		return err
+	}
+	return out.transaction(tr)
+}
+
+func refund(ctx context.Context, s contracts.ApiService, args []string, out printer) error {
+	f := newMoneyFlags("refund")
+	reason := f.fs.String("reason", "", "refund reason")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	if *reason == "" {
+		return fmt.Errorf("%w: -reason is required", errUsage)
+	}
+
+	tr, err := s.Refund(ctx, *vo.NewRefundRequest(*f.uid, *f.amount, *reason))
+	if err != nil {
+		return err
+	}
+	return out.transaction(tr)
+}
+
+func testPayment(ctx context.Context, s contracts.ApiService, args []string, out printer) error {
+	fs := flag.NewFlagSet("test-payment", flag.ContinueOnError)
+	amount := fs.Int64("amount", 0, "amount in minimal currency units, e.g. 1000 for 10.00")
+	currency := fs.String("currency", "", "currency in ISO-4217 format, e.g. BYN")
+	trackingId := fs.String("tracking-id", "", "tracking id, generated if empty")
+	card := fs.String("card", testCardNumber, "test card number")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *amount <= 0 || *currency == "" {
+		return fmt.Errorf("%w: positive -amount and -currency are required", errUsage)
+	}
+	if *trackingId == "" {
+		*trackingId = "cli-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	expYear := strconv.Itoa(time.Now().Year() + 1)
+	cc := vo.NewCreditCard(*card, "123", "TEST CARDHOLDER", "01", expYear)
+	r := vo.NewPaymentRequest(*amount, *currency, "bepaid cli test payment", *trackingId, true, *cc)
+
+	tr, err := s.Payment(ctx, *r)
+	if err != nil {
+		return err
+	}
+	return out.transaction(tr)
+}
@@ -0,0 +1,31 @@
+// Command bepaid checks, captures, voids and refunds bePaid transactions.
+//
+// Usage:
+//
+//	bepaid [-config file] [-o table|json] <command> [flags]
+//
+// Commands:
+//
+//	status        -uid UID | -tracking-id ID
+//	capture       -uid UID -amount N -yes
+//	void          -uid UID -amount N -yes
+//	refund        -uid UID -amount N -reason TEXT -yes
+//	test-payment  -amount N -currency CUR [-tracking-id ID] [-card NUMBER]
+//
+// Settings are read by config.Load from the config file and BEPAID_* environment variables
+// (BEPAID_SHOP_ID, BEPAID_SECRET_KEY, BEPAID_BASE_URL, ...), environment has priority.
+package main
+
+import (
+	"bepaid-sdk/config"
+	"bepaid-sdk/service/contracts"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.LookupEnv, os.Stdout, os.Stderr, newService))
+}
+
+func newService(c config.Config) contracts.ApiService {
+	return c.NewApiService()
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bepaid-sdk/config"
+	"bepaid-sdk/service/contracts"
+	"bepaid-sdk/service/vo"
+	"bepaid-sdk/testdata"
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func env(m map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) {
+		v, ok := m[k]
+		return v, ok
+	}
+}
+
+var credentials = env(map[string]string{"BEPAID_SHOP_ID": "361", "BEPAID_SECRET_KEY": "secret"})
+
+func runWith(t *testing.T, s contracts.ApiService, lookupEnv func(string) (string, bool), args ...string) (int, string, string) {
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	code := run(args, lookupEnv, &stdout, &stderr, func(c config.Config) contracts.ApiService {
+		assert.Equal(t, "361", c.ShopId)
+		assert.Equal(t, config.DefaultBaseUrl, c.BaseUrl)
+		return s
+	})
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_StatusTable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tr := vo.TransactionResponse{}
+	tr.Transaction = vo.Transaction{Uid: "1-a", Type: "payment", Status: "successful", Amount: 100, Currency: "BYN", TrackingId: "order-1", Message: "Successfully processed"}
+
+	s := testdata.NewMockApiService(ctrl)
+	s.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(tr, nil)
+
+	code, stdout, _ := runWith(t, s, credentials, "status", "-uid", "1-a")
+
+	assert.Equal(t, exitOk, code)
+	assert.Equal(t, "UID  TYPE     STATUS      AMOUNT  CURRENCY  PARENT UID  TRACKING ID  TEST   MESSAGE\n"+
+		"1-a  payment  successful  100     BYN       -           order-1      false  Successfully processed\n", stdout)
+}
+
+func TestRun_StatusJSONGatewayError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := testdata.NewMockApiService(ctrl)
+	s.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(vo.TransactionsResponse{Response: vo.ErrorResponse{Message: "Unauthorized"}}, nil)
+
+	code, _, stderr := runWith(t, s, credentials, "-o", "json", "status", "-tracking-id", "order-1")
+
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "gateway: Unauthorized\n", stderr)
+}
+
+func TestRun_RefundRequiresConfirmation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := testdata.NewMockApiService(ctrl)
+
+	code, _, stderr := runWith(t, s, credentials, "refund", "-uid", "1-a", "-amount", "100", "-reason", "return")
+
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "requires -yes")
+}
+
+func TestRun_RefundConfirmed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := testdata.NewMockApiService(ctrl)
+	s.EXPECT().Refund(gomock.Any(), *vo.NewRefundRequest("1-a", 100, "return")).Return(vo.TransactionResponse{}, nil)
+
+	code, _, _ := runWith(t, s, credentials, "refund", "-uid", "1-a", "-amount", "100", "-reason", "return", "-yes")
+
+	assert.Equal(t, exitOk, code)
+}
+
+func TestRun_MissingCredentials(t *testing.T) {
+	code, _, stderr := runWith(t, nil, env(nil), "status", "-uid", "1-a")
+
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "shop_id is required")
+}
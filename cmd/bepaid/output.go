@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bepaid-sdk/service/vo"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+type printer struct {
+	json bool
+	w    io.Writer
+}
+
+func newPrinter(format string, w io.Writer) (printer, error) {
+	switch format {
+	case "table":
+		return printer{w: w}, nil
+	case "json":
+		return printer{json: true, w: w}, nil
+	}
+	return printer{}, fmt.Errorf("unknown output format %q", format)
+}
+
+// transaction prints the transaction or returns gateway error
+func (p printer) transaction(tr vo.TransactionResponse) error {
+	if tr.IsError() {
+		return gatewayError(tr.Response)
+	}
+	if p.json {
+		return p.encode(tr)
+	}
+	return p.table([]vo.Transaction{tr.Transaction})
+}
+
+func (p printer) transactions(tr vo.TransactionsResponse) error {
+	if tr.Response.Message != "" {
+		return gatewayError(tr.Response)
+	}
+	if p.json {
+		return p.encode(tr)
+	}
+	return p.table(tr.Transactions)
+}
+
+func (p printer) encode(v interface{}) error {
+	e := json.NewEncoder(p.w)
+	e.SetIndent("", "  ")
+	return e.Encode(v)
+}
+
+func (p printer) table(transactions []vo.Transaction) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "UID\tTYPE\tSTATUS\tAMOUNT\tCURRENCY\tPARENT UID\tTRACKING ID\tTEST\tMESSAGE")
+	for _, t := range transactions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%t\t%s\n",
+			t.Uid, t.Type, t.Status, t.Amount, t.Currency, dash(t.ParentUid), dash(t.TrackingId), t.Test, t.Message)
+	}
+
+	return tw.Flush()
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func gatewayError(r vo.ErrorResponse) error {
+	if len(r.Errors) == 0 {
+		return fmt.Errorf("gateway: %s", r.Message)
+	}
+	return fmt.Errorf("gateway: %s %v", r.Message, r.Errors)
+}
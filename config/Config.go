@@ -0,0 +1,353 @@
+package config
+
+import (
+	"bepaid-sdk/api"
+	"bepaid-sdk/service"
+	"bepaid-sdk/service/vo"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultBaseUrl = "https://gateway.bepaid.by"
+	DefaultTimeout = 60 * time.Second
+
+	// EnvConfigFile is used by Load if file argument is empty
+	EnvConfigFile = "BEPAID_CONFIG"
+
+	redacted = "[REDACTED]"
+)
+
+// Config of Api and ApiService.
+//
+// Values are taken from file first, then from environment variables (see Load)
+type Config struct {
+	ShopId string `json:"shop_id" yaml:"shop_id"`
+
+	// ShopIdFile is a path of file with shop id, e.g. mounted secret. Used if ShopId is empty
+	ShopIdFile string `json:"shop_id_file" yaml:"shop_id_file"`
+
+	SecretKey string `json:"secret_key" yaml:"secret_key"`
+
+	// SecretKeyFile is a path of file with secret key, e.g. mounted secret. Used if SecretKey is empty
+	SecretKeyFile string `json:"secret_key_file" yaml:"secret_key_file"`
+
+	BaseUrl string `json:"base_url" yaml:"base_url"`
+
+	// Language of gateway messages, e.g. ru, be, en. Empty means the gateway default
+	Language string `json:"language" yaml:"language"`
+
+	// TestMode makes every payment and authorization a test transaction
+	TestMode bool `json:"test_mode" yaml:"test_mode"`
+
+	// Timeout of one http request
+	Timeout Duration `json:"timeout" yaml:"timeout"`
+
+	// Retry of status requests
+	Retry Retry `json:"retry" yaml:"retry"`
+
+	TLS TLS `json:"tls" yaml:"tls"`
+}
+
+type Retry struct {
+	MaxAttempts    int      `json:"max_attempts" yaml:"max_attempts"`
+	InitialBackoff Duration `json:"initial_backoff" yaml:"initial_backoff"`
+	MaxBackoff     Duration `json:"max_backoff" yaml:"max_backoff"`
+}
+
+// Default returns config without credentials
+func Default() Config {
+	return Config{
+		BaseUrl: DefaultBaseUrl,
+		Timeout: Duration(DefaultTimeout),
+	}
+}
+
+// Load builds config from defaults, file and environment variables, resolves secret files and validates the result.
+//
+// If file is empty, BEPAID_CONFIG environment variable is used. Empty file name means no file
+func Load(file string) (Config, error) {
+	return LoadWith(file, os.LookupEnv)
+}
+
+// LoadWith is Load reading environment with lookup
+func LoadWith(file string, lookup func(string) (string, bool)) (Config, error) {
+	if file == "" {
+		file, _ = lookup(EnvConfigFile)
+	}
+
+	c := Default()
+	if file != "" {
+		if err := c.LoadFile(file); err != nil {
+			return c, err
+		}
+	}
+
+	if err := c.ApplyEnv(lookup); err != nil {
+		return c, err
+	}
+	if err := c.ResolveSecretFiles(); err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
+
+// LoadFile overrides fields present in YAML or JSON file. Format is chosen by extension, YAML is default.
+// Unknown keys are errors
+func (c *Config) LoadFile(file string) error {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	// unknown keys are rejected, so a typo doesn't silently leave the default value
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.DisallowUnknownFields()
+		err = d.Decode(c)
+	default:
+		d := yaml.NewDecoder(bytes.NewReader(b))
+		d.KnownFields(true)
+		if err = d.Decode(c); err == io.EOF {
+			// empty file
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("config %s: %w", file, err)
+	}
+	return nil
+}
+
+// ApplyEnv overrides fields with environment variables:
+//
+//	BEPAID_SHOP_ID, BEPAID_SHOP_ID_FILE, BEPAID_SECRET_KEY, BEPAID_SECRET_KEY_FILE,
+//	BEPAID_BASE_URL, BEPAID_LANGUAGE, BEPAID_TEST_MODE, BEPAID_TIMEOUT,
+//	BEPAID_RETRY_MAX_ATTEMPTS, BEPAID_RETRY_INITIAL_BACKOFF, BEPAID_RETRY_MAX_BACKOFF,
+//	BEPAID_TLS_CA_FILE, BEPAID_TLS_CERT_FILE, BEPAID_TLS_KEY_FILE, BEPAID_TLS_PINS (comma separated)
+//
+// BEPAID_SHOP_ID_FILE and BEPAID_SECRET_KEY_FILE override shop id and secret key of the config file,
+// they are read by ResolveSecretFiles. lookup is usually os.LookupEnv
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	strs := map[string]*string{
+		"BEPAID_SHOP_ID":         &c.ShopId,
+		"BEPAID_SHOP_ID_FILE":    &c.ShopIdFile,
+		"BEPAID_SECRET_KEY":      &c.SecretKey,
+		"BEPAID_SECRET_KEY_FILE": &c.SecretKeyFile,
+		"BEPAID_BASE_URL":        &c.BaseUrl,
+		"BEPAID_LANGUAGE":        &c.Language,
+		"BEPAID_TLS_CA_FILE":     &c.TLS.CAFile,
+		"BEPAID_TLS_CERT_FILE":   &c.TLS.CertFile,
+		"BEPAID_TLS_KEY_FILE":    &c.TLS.KeyFile,
+	}
+	for env, field := range strs {
+		if v, ok := lookup(env); ok {
+			*field = v
+		}
+	}
+
+	// file reference from environment has priority over the value from config file,
+	// the value itself from environment has priority over any file
+	secrets := []struct {
+		env, fileEnv string
+		value        *string
+	}{
+		{"BEPAID_SHOP_ID", "BEPAID_SHOP_ID_FILE", &c.ShopId},
+		{"BEPAID_SECRET_KEY", "BEPAID_SECRET_KEY_FILE", &c.SecretKey},
+	}
+	for _, s := range secrets {
+		_, fromEnv := lookup(s.env)
+		if _, fileFromEnv := lookup(s.fileEnv); fileFromEnv && !fromEnv {
+			*s.value = ""
+		}
+	}
+
+	if v, ok := lookup("BEPAID_TLS_PINS"); ok {
+		c.TLS.Pins = nil
+		for _, pin := range strings.Split(v, ",") {
+			if pin = strings.TrimSpace(pin); pin != "" {
+				c.TLS.Pins = append(c.TLS.Pins, pin)
+			}
+		}
+	}
+
+	if v, ok := lookup("BEPAID_TEST_MODE"); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("BEPAID_TEST_MODE: %w", err)
+		}
+		c.TestMode = b
+	}
+
+	if v, ok := lookup("BEPAID_RETRY_MAX_ATTEMPTS"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("BEPAID_RETRY_MAX_ATTEMPTS: %w", err)
+		}
+		c.Retry.MaxAttempts = n
+	}
+
+	durations := map[string]*Duration{
+		"BEPAID_TIMEOUT":               &c.Timeout,
+		"BEPAID_RETRY_INITIAL_BACKOFF": &c.Retry.InitialBackoff,
+		"BEPAID_RETRY_MAX_BACKOFF":     &c.Retry.MaxBackoff,
+	}
+	for env, field := range durations {
+		if v, ok := lookup(env); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("%s: %w", env, err)
+			}
+			*field = Duration(d)
+		}
+	}
+
+	return nil
+}
+
+// ResolveSecretFiles reads ShopIdFile and SecretKeyFile if ShopId or SecretKey is empty
+func (c *Config) ResolveSecretFiles() error {
+	for _, s := range []struct {
+		file  string
+		value *string
+	}{
+		{c.ShopIdFile, &c.ShopId},
+		{c.SecretKeyFile, &c.SecretKey},
+	} {
+		if s.file == "" || *s.value != "" {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(s.file)
+		if err != nil {
+			return err
+		}
+		*s.value = strings.TrimSpace(string(b))
+	}
+
+	return nil
+}
+
+// ValidationError lists all problems of config
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid bepaid config: " + strings.Join(e.Problems, "; ")
+}
+
+var ErrInvalid = errors.New("invalid bepaid config")
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+func (c Config) Validate() error {
+	var problems []string
+
+	if c.ShopId == "" {
+		problems = append(problems, "shop_id is required")
+	}
+	if c.SecretKey == "" {
+		problems = append(problems, "secret_key is required")
+	}
+
+	if u, err := url.Parse(c.BaseUrl); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		problems = append(problems, fmt.Sprintf("base_url %q must be absolute http(s) url", c.BaseUrl))
+	}
+
+	if c.Language != "" && !isLanguageCode(c.Language) {
+		problems = append(problems, fmt.Sprintf("language %q must be ISO 639-1 code, e.g. ru, be, en", c.Language))
+	}
+
+	// zero timeout of http.Client means no timeout at all
+	if c.Timeout <= 0 {
+		problems = append(problems, "timeout must be positive")
+	}
+	if c.Retry.MaxAttempts < 0 {
+		problems = append(problems, "retry.max_attempts must not be negative")
+	}
+	if c.Retry.InitialBackoff < 0 || c.Retry.MaxBackoff < 0 {
+		problems = append(problems, "retry backoff must not be negative")
+	}
+	if c.Retry.MaxBackoff > 0 && c.Retry.InitialBackoff > c.Retry.MaxBackoff {
+		problems = append(problems, "retry.initial_backoff must not exceed retry.max_backoff")
+	}
+
+	if _, err := c.TransportSettings(); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func isLanguageCode(s string) bool {
+	return len(s) == 2 && s[0] >= 'a' && s[0] <= 'z' && s[1] >= 'a' && s[1] <= 'z'
+}
+
+// Redacted returns copy of config with secret key replaced
+func (c Config) Redacted() Config {
+	if c.SecretKey != "" {
+		c.SecretKey = redacted
+	}
+	return c
+}
+
+// String prints every field of effective config without secret key
+func (c Config) String() string {
+	c = c.Redacted()
+	return fmt.Sprintf("shop_id=%s shop_id_file=%s secret_key=%s secret_key_file=%s base_url=%s language=%s test_mode=%t timeout=%s "+
+		"retry.max_attempts=%d retry.initial_backoff=%s retry.max_backoff=%s "+
+		"tls.ca_file=%s tls.cert_file=%s tls.key_file=%s tls.pins=%s",
+		c.ShopId, c.ShopIdFile, c.SecretKey, c.SecretKeyFile, c.BaseUrl, c.Language, c.TestMode, c.Timeout,
+		c.Retry.MaxAttempts, c.Retry.InitialBackoff, c.Retry.MaxBackoff,
+		c.TLS.CAFile, c.TLS.CertFile, c.TLS.KeyFile, strings.Join(c.TLS.Pins, ","))
+}
+
+// GoString keeps secret key out of %#v
+func (c Config) GoString() string {
+	return "config.Config{" + c.String() + "}"
+}
+
+// NewApi uses api.NewTransport with TLS settings of c. If they are invalid (see Validate), every request fails
+func (c Config) NewApi() *api.Api {
+	var transport http.RoundTripper
+	if settings, err := c.TransportSettings(); err != nil {
+		transport = failingTransport{err: err}
+	} else {
+		transport = api.NewTransport(settings)
+	}
+	client := &http.Client{Transport: transport, Timeout: time.Duration(c.Timeout)}
+
+	return api.NewApi(client, c.BaseUrl, c.ShopId, c.SecretKey).
+		WithTestMode(c.TestMode).
+		WithLanguage(vo.Language(c.Language)).
+		WithRetryPolicy(api.RetryPolicy{
+			MaxAttempts:    c.Retry.MaxAttempts,
+			InitialBackoff: time.Duration(c.Retry.InitialBackoff),
+			MaxBackoff:     time.Duration(c.Retry.MaxBackoff),
+		})
+}
+
+func (c Config) NewApiService() *service.ApiService {
+	return service.NewApiService(c.NewApi())
+}
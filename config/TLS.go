@@ -0,0 +1,70 @@
+package config
+
+import (
+	"bepaid-sdk/api"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// TLS of connections to the gateway. Zero value uses system roots without client certificate
+type TLS struct {
+	// CAFile is a PEM file with root certificates of the gateway. System roots are used if empty
+	CAFile string `json:"ca_file" yaml:"ca_file"`
+
+	// CertFile and KeyFile are PEM client certificate and key for mutual TLS
+	CertFile string `json:"cert_file" yaml:"cert_file"`
+	KeyFile  string `json:"key_file" yaml:"key_file"`
+
+	// Pins of gateway keys, base64 SHA-256 of SubjectPublicKeyInfo, see api.Pin
+	Pins []string `json:"pins" yaml:"pins"`
+}
+
+// TransportSettings reads files and parses pins of c.TLS
+func (c Config) TransportSettings() (api.TransportSettings, error) {
+	var s api.TransportSettings
+
+	if c.TLS.CAFile != "" {
+		b, err := ioutil.ReadFile(c.TLS.CAFile)
+		if err != nil {
+			return s, fmt.Errorf("tls.ca_file: %w", err)
+		}
+		s.RootCAs = x509.NewCertPool()
+		if !s.RootCAs.AppendCertsFromPEM(b) {
+			return s, fmt.Errorf("tls.ca_file: no certificates in %s", c.TLS.CAFile)
+		}
+	}
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		return s, errors.New("tls.cert_file and tls.key_file must be set together")
+	}
+	if c.TLS.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.TLS.CertFile, c.TLS.KeyFile)
+		if err != nil {
+			return s, fmt.Errorf("tls client certificate: %w", err)
+		}
+		s.Certificates = []tls.Certificate{cert}
+	}
+
+	for _, pin := range c.TLS.Pins {
+		p, err := api.ParsePin(pin)
+		if err != nil {
+			return s, fmt.Errorf("tls.pins: %w", err)
+		}
+		s.Pins = append(s.Pins, p)
+	}
+
+	return s, nil
+}
+
+// failingTransport fails every request, so invalid TLS settings never fall back to a weaker connection
+type failingTransport struct {
+	err error
+}
+
+func (t failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
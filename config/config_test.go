@@ -0,0 +1,216 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func env(m map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) {
+		v, ok := m[k]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadWith_Yaml(t *testing.T) {
+	file := writeFile(t, "bepaid.yaml", `
+shop_id: "361"
+secret_key: secret
+test_mode: true
+timeout: 15s
+retry:
+  max_attempts: 3
+  initial_backoff: 200ms
+  max_backoff: 2s
+`)
+
+	c, err := LoadWith(file, env(nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "361", c.ShopId)
+	assert.Equal(t, "secret", c.SecretKey)
+	assert.Equal(t, DefaultBaseUrl, c.BaseUrl)
+	assert.True(t, c.TestMode)
+	assert.Equal(t, Duration(15*time.Second), c.Timeout)
+	assert.Equal(t, Retry{3, Duration(200 * time.Millisecond), Duration(2 * time.Second)}, c.Retry)
+}
+
+func TestLoadWith_JsonAndEnvPriority(t *testing.T) {
+	file := writeFile(t, "bepaid.json", `{"shop_id":"361","secret_key":"secret","base_url":"https://file.test","timeout":"5s"}`)
+
+	c, err := LoadWith("", env(map[string]string{
+		EnvConfigFile:               file,
+		"BEPAID_BASE_URL":           "https://env.test",
+		"BEPAID_TEST_MODE":          "true",
+		"BEPAID_RETRY_MAX_ATTEMPTS": "4",
+		"BEPAID_TIMEOUT":            "30s",
+	}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "361", c.ShopId)
+	assert.Equal(t, "https://env.test", c.BaseUrl)
+	assert.True(t, c.TestMode)
+	assert.Equal(t, 4, c.Retry.MaxAttempts)
+	assert.Equal(t, Duration(30*time.Second), c.Timeout)
+}
+
+func TestLoadWith_SecretFiles(t *testing.T) {
+	shopId := writeFile(t, "shop_id", "361\n")
+	secret := writeFile(t, "secret_key", "  secret\n")
+
+	c, err := LoadWith("", env(map[string]string{
+		"BEPAID_SHOP_ID_FILE":    shopId,
+		"BEPAID_SECRET_KEY_FILE": secret,
+	}))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "361", c.ShopId)
+	assert.Equal(t, "secret", c.SecretKey)
+}
+
+func TestLoadWith_SecretFileFromEnvOverridesConfigFile(t *testing.T) {
+	file := writeFile(t, "bepaid.yaml", `
+shop_id: "361"
+secret_key: from-config
+`)
+	secret := writeFile(t, "secret_key", "from-env-file\n")
+
+	c, err := LoadWith(file, env(map[string]string{"BEPAID_SECRET_KEY_FILE": secret}))
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env-file", c.SecretKey)
+	assert.Equal(t, "361", c.ShopId)
+
+	c, err = LoadWith(file, env(map[string]string{"BEPAID_SECRET_KEY_FILE": secret, "BEPAID_SECRET_KEY": "from-env"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "from-env", c.SecretKey)
+}
+
+func TestLoadWith_InvalidEnv(t *testing.T) {
+	_, err := LoadWith("", env(map[string]string{"BEPAID_TIMEOUT": "soon"}))
+
+	assert.ErrorContains(t, err, "BEPAID_TIMEOUT")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	c := Default()
+	c.BaseUrl = "gateway.bepaid.by"
+	c.Language = "Russian"
+	c.Retry = Retry{MaxAttempts: -1, InitialBackoff: Duration(time.Second), MaxBackoff: Duration(time.Millisecond)}
+
+	err := c.Validate()
+
+	var v *ValidationError
+	assert.True(t, errors.As(err, &v))
+	assert.True(t, errors.Is(err, ErrInvalid))
+	assert.Equal(t, []string{
+		"shop_id is required",
+		"secret_key is required",
+		`base_url "gateway.bepaid.by" must be absolute http(s) url`,
+		`language "Russian" must be ISO 639-1 code, e.g. ru, be, en`,
+		"retry.max_attempts must not be negative",
+		"retry.initial_backoff must not exceed retry.max_backoff",
+	}, v.Problems)
+}
+
+func TestConfig_ValidateZeroTimeout(t *testing.T) {
+	c := Default()
+	c.ShopId, c.SecretKey = "361", "secret"
+	c.Timeout = 0
+
+	var v *ValidationError
+	assert.True(t, errors.As(c.Validate(), &v))
+	assert.Equal(t, []string{"timeout must be positive"}, v.Problems)
+}
+
+func TestLoadWith_UnknownKeys(t *testing.T) {
+	for name, content := range map[string]string{
+		"bepaid.yaml": "shop_id: \"361\"\nsecret_key: secret\ntimout: 15s\n",
+		"bepaid.json": `{"shop_id":"361","secret_key":"secret","retry":{"max_attempt":3}}`,
+	} {
+		_, err := LoadWith(writeFile(t, name, content), env(nil))
+		assert.Error(t, err, name)
+	}
+
+	c, err := LoadWith(writeFile(t, "empty.yaml", ""), env(map[string]string{"BEPAID_SHOP_ID": "361", "BEPAID_SECRET_KEY": "secret"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "361", c.ShopId)
+}
+
+func TestConfig_StringIsRedacted(t *testing.T) {
+	c := Default()
+	c.ShopId = "361"
+	c.SecretKey = "very-secret"
+	c.Language = "be"
+	c.TLS = TLS{CAFile: "/etc/bepaid/ca.pem", Pins: []string{"pin1", "pin2"}}
+
+	for _, s := range []string{c.String(), fmt.Sprintf("%v", c), fmt.Sprintf("%+v", c), fmt.Sprintf("%#v", c)} {
+		assert.NotContains(t, s, "very-secret")
+		assert.Contains(t, s, redacted)
+		assert.Contains(t, s, "shop_id=361")
+		assert.Contains(t, s, "language=be")
+		assert.Contains(t, s, "tls.ca_file=/etc/bepaid/ca.pem")
+		assert.Contains(t, s, "tls.pins=pin1,pin2")
+	}
+	assert.Equal(t, "very-secret", c.SecretKey)
+}
+
+func TestConfig_NewApi(t *testing.T) {
+	c := Default()
+	c.BaseUrl = "https://gateway.test/"
+
+	assert.Equal(t, "https://gateway.test", c.NewApi().GetUrl())
+}
+
+func TestLoadWith_TLS(t *testing.T) {
+	pin := "sha256/" + strings.Repeat("A", 43) + "="
+	file := writeFile(t, "bepaid.yaml", `
+shop_id: "361"
+secret_key: secret
+tls:
+  pins: ["`+pin+`"]
+`)
+
+	c, err := LoadWith(file, env(nil))
+	assert.NoError(t, err)
+	settings, err := c.TransportSettings()
+	assert.NoError(t, err)
+	assert.Len(t, settings.Pins, 1)
+
+	_, err = LoadWith(file, env(map[string]string{
+		"BEPAID_TLS_PINS":      pin + ", invalid",
+		"BEPAID_TLS_CERT_FILE": "client.pem",
+	}))
+	assert.ErrorIs(t, err, ErrInvalid)
+	assert.ErrorContains(t, err, "tls.cert_file and tls.key_file must be set together")
+
+	_, err = LoadWith(file, env(map[string]string{"BEPAID_TLS_PINS": pin + ", invalid"}))
+	assert.ErrorContains(t, err, "tls.pins")
+
+	_, err = LoadWith(file, env(map[string]string{"BEPAID_TLS_CA_FILE": writeFile(t, "ca.pem", "not pem")}))
+	assert.ErrorContains(t, err, "no certificates")
+}
+
+func TestConfig_NewApiWithInvalidTLSFailsRequests(t *testing.T) {
+	c := Default()
+	c.TLS.Pins = []string{"invalid"}
+
+	_, err := c.NewApi().StatusByUid(context.Background(), "1-a")
+
+	assert.ErrorContains(t, err, "tls.pins")
+}
@@ -0,0 +1,109 @@
+package jsonfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const tempPrefix = "tmp-"
+
+// Dir keeps every value as a JSON file named by namespace and hash of its key, so any key is a valid file name
+// and stores of different namespaces can share one directory
+type Dir struct {
+	mu        sync.Mutex
+	dir       string
+	namespace string
+}
+
+// OpenDir creates dir if it doesn't exist. Files of namespace are named "<namespace>-<hash>.json"
+func OpenDir(dir, namespace string) (*Dir, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &Dir{dir: dir, namespace: namespace}, nil
+}
+
+// Read decodes value of key to v. ok is false if there is no such key
+func (d *Dir) Read(key string, v interface{}) (ok bool, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	err = read(d.path(key), v)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Write writes v to temporary file and renames it, so a crash never leaves partially written value
+func (d *Dir) Write(key string, v interface{}) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(d.dir, tempPrefix)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), d.path(key))
+}
+
+// Each decodes every value of the namespace with decode, e.g. to collect values matching a condition
+func (d *Dir) Each(decode func(unmarshal func(v interface{}) error) error) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	files, err := ioutil.ReadDir(d.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasPrefix(file.Name(), d.namespace+"-") || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(d.dir, file.Name())
+		if err = decode(func(v interface{}) error { return read(path, v) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dir) path(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, d.namespace+"-"+hex.EncodeToString(h[:])+".json")
+}
+
+func read(path string, v interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,74 @@
+// Package jsonfile keeps JSON values in files so that a crash never leaves a partially written value behind
+package jsonfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// Lines is a file of JSON lines opened for appending. It is not safe for concurrent use
+type Lines struct {
+	file *os.File
+	size int64
+}
+
+// OpenLines opens or creates the file and returns its complete lines without line breaks.
+// A partially written last line, e.g. after a crash, is removed
+func OpenLines(path string) (*Lines, [][]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, err
+	}
+
+	complete := b[:bytes.LastIndexByte(b, '\n')+1]
+	if len(complete) < len(b) {
+		if err = os.Truncate(path, int64(len(complete))); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var lines [][]byte
+	if len(complete) > 0 {
+		lines = bytes.Split(complete[:len(complete)-1], []byte{'\n'})
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &Lines{file: file, size: int64(len(complete))}, lines, nil
+}
+
+// Append writes v as a line and syncs the file. If writing fails, the file is truncated back,
+// so the next line doesn't follow a partial one
+func (l *Lines) Append(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	n, err := l.file.Write(append(b, '\n'))
+	if err == nil {
+		err = l.file.Sync()
+	}
+	if err != nil {
+		if n > 0 {
+			if truncErr := l.file.Truncate(l.size); truncErr != nil {
+				return fmt.Errorf("%w, truncate: %v", err, truncErr)
+			}
+		}
+		return err
+	}
+
+	l.size += int64(n)
+	return nil
+}
+
+func (l *Lines) Close() error {
+	return l.file.Close()
+}
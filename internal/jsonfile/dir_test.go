@@ -0,0 +1,62 @@
+package jsonfile
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDir_ReadWriteEach(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values")
+	d, err := OpenDir(path, "values")
+	assert.NoError(t, err)
+	other, err := OpenDir(path, "other")
+	assert.NoError(t, err)
+
+	var v string
+	ok, err := d.Read("a/b", &v)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, d.Write("a/b", "first"))
+	assert.NoError(t, d.Write("a/b", "second"))
+	assert.NoError(t, d.Write("c", "third"))
+	// the same key in another namespace of the directory
+	assert.NoError(t, other.Write("a/b", "other"))
+	// left by a crash during Write
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(path, tempPrefix+"1"), []byte(`"partial`), 0600))
+
+	ok, err = d.Read("a/b", &v)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "second", v)
+
+	var all []string
+	err = d.Each(func(unmarshal func(v interface{}) error) error {
+		var s string
+		err := unmarshal(&s)
+		all = append(all, s)
+		return err
+	})
+	sort.Strings(all)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"second", "third"}, all)
+}
+
+func TestDir_NamespacesDontOverwrite(t *testing.T) {
+	path := t.TempDir()
+	a, _ := OpenDir(path, "a")
+	b, _ := OpenDir(path, "b")
+
+	assert.NoError(t, a.Write("key", 1))
+	assert.NoError(t, b.Write("key", 2))
+
+	var v int
+	ok, err := a.Read("key", &v)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
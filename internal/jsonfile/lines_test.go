@@ -0,0 +1,48 @@
+package jsonfile
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenLines_RemovesPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lines.jsonl")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("{\"a\":1}\n{\"a\":2}\n{\"a\":"), 0600))
+
+	l, lines, err := OpenLines(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"a":1}`, `{"a":2}`}, texts(lines))
+
+	assert.NoError(t, l.Append(map[string]int{"a": 3}))
+	assert.NoError(t, l.Close())
+
+	b, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "{\"a\":1}\n{\"a\":2}\n{\"a\":3}\n", string(b))
+}
+
+func TestLines_AppendFailureKeepsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lines.jsonl")
+
+	l, lines, err := OpenLines(path)
+	assert.NoError(t, err)
+	assert.Empty(t, lines)
+	assert.NoError(t, l.Append(1))
+	assert.Error(t, l.Append(func() {}))
+	assert.NoError(t, l.Close())
+	assert.Error(t, l.Append(2))
+
+	_, lines, err = OpenLines(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1"}, texts(lines))
+}
+
+func texts(lines [][]byte) []string {
+	result := make([]string, 0, len(lines))
+	for _, l := range lines {
+		result = append(result, string(l))
+	}
+	return result
+}
@@ -0,0 +1,76 @@
+// Package redact replaces card and personal data in JSON bodies, e.g. before they are written
+// to cassettes or event logs
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Redacted replaces values of sensitive fields
+const Redacted = "[REDACTED]"
+
+// Redactor replaces values of sensitive JSON fields in request and response bodies. Nil Redactor returns bodies unchanged
+type Redactor struct {
+	fields map[string]struct{}
+}
+
+// New redacts values of fields with given names at any depth
+func New(fields ...string) *Redactor {
+	r := &Redactor{fields: make(map[string]struct{}, len(fields))}
+	for _, f := range fields {
+		r.fields[f] = struct{}{}
+	}
+	return r
+}
+
+// Default redacts card data, personal data of customer and billing address
+func Default() *Redactor {
+	return New("number", "verification_value", "holder", "token", "stamp", "email", "ip", "phone", "birth_date",
+		"first_name", "last_name", "address", "city", "zip", "country", "state")
+}
+
+// Redact returns body with values of sensitive fields replaced. Body is returned unchanged if it's not JSON
+func (r *Redactor) Redact(body []byte) []byte {
+	return []byte(r.RedactString(string(body)))
+}
+
+// RedactString is Redact of string body
+func (r *Redactor) RedactString(body string) string {
+	if r == nil || len(r.fields) == 0 || body == "" {
+		return body
+	}
+
+	// numbers are kept as written, e.g. 1e3 or 10.50, instead of conversion to float64
+	d := json.NewDecoder(bytes.NewReader([]byte(body)))
+	d.UseNumber()
+
+	var v interface{}
+	if err := d.Decode(&v); err != nil || d.More() {
+		return body
+	}
+
+	b, err := json.Marshal(r.walk(v))
+	if err != nil {
+		return body
+	}
+	return string(b)
+}
+
+func (r *Redactor) walk(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if _, ok := r.fields[k]; ok && field != nil {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = r.walk(field)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = r.walk(v[i])
+		}
+	}
+	return v
+}
@@ -0,0 +1,34 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	b := Default().Redact([]byte(`{"transactions":[{"uid":"1-a","credit_card":{"token":"t1","last_4":"0000"},"customer":{"email":"a@b.c","ip":null}}]}`))
+
+	assert.Equal(t, `{"transactions":[{"credit_card":{"last_4":"0000","token":"[REDACTED]"},"customer":{"email":"[REDACTED]","ip":null},"uid":"1-a"}]}`, string(b))
+}
+
+func TestRedactor_BillingAddress(t *testing.T) {
+	b := Default().Redact([]byte(`{"billing_address":{"first_name":"Ivan","last_name":"Ivanov","address":"Nezavisimosti 1",` +
+		`"city":"Minsk","zip":"220000","country":"BY","state":"","phone":"+375291234567"}}`))
+
+	assert.Equal(t, `{"billing_address":{"address":"[REDACTED]","city":"[REDACTED]","country":"[REDACTED]",`+
+		`"first_name":"[REDACTED]","last_name":"[REDACTED]","phone":"[REDACTED]","state":"[REDACTED]","zip":"[REDACTED]"}}`, string(b))
+}
+
+func TestRedactor_KeepsNumbers(t *testing.T) {
+	b := Default().Redact([]byte(`{"amount":12345678901234567890,"rate":10.50,"fee":1e3,"credit_card":{"number":"4200000000000000"}}`))
+
+	assert.Equal(t, `{"amount":12345678901234567890,"credit_card":{"number":"[REDACTED]"},"fee":1e3,"rate":10.50}`, string(b))
+}
+
+func TestRedactor_NotJSON(t *testing.T) {
+	var nilRedactor *Redactor
+
+	assert.Equal(t, "<html>", Default().RedactString("<html>"))
+	assert.Equal(t, `{"number":"4200"}`, nilRedactor.RedactString(`{"number":"4200"}`))
+}
@@ -4,60 +4,126 @@ import (
 	"bepaid-sdk/api/contracts"
 	"bepaid-sdk/service/vo"
 	"context"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"io"
-	"log"
 )
 
 type ApiService struct {
 	api contracts.Api
+
+	// check transaction lifecycle before capture, void and refund
+	lifecycleGuard bool
+
+	// limit of response body, DefaultMaxBodySize if zero
+	maxBodySize int64
+
+	// report fields unknown to the SDK with *UnknownFieldsError
+	strictDecoding bool
+
+	recorder Recorder
+}
+
+// Names of operations passed to Recorder
+const (
+	RecordPayment            = "payment"
+	RecordAuthorization      = "authorization"
+	RecordCapture            = "capture"
+	RecordVoid               = "void"
+	RecordRefund             = "refund"
+	RecordStatusByUid        = "status_by_uid"
+	RecordStatusByTrackingId = "status_by_tracking_id"
+)
+
+// Recorder receives every request of ApiService with decoded response or error, e.g. to keep an audit trail.
+// See eventlog.Recorder
+type Recorder interface {
+	Record(ctx context.Context, operation string, request interface{}, response interface{}, err error)
 }
 
 func NewApiService(api contracts.Api) *ApiService {
 	return &ApiService{api: api}
 }
 
-func (a ApiService) Authorizations(ctx context.Context, authorizationRequest vo.AuthorizationRequest) (vo.TransactionResponse, error) {
-	resp, err := a.api.Authorization(ctx, authorizationRequest)
-	if err != nil {
+// WithLifecycleGuard makes Capture, Void and Refund check state of the parent transaction
+// before calling the gateway. Operations not allowed in current state fail with *OperationNotAllowedError.
+//
+// Every guarded call costs additional status requests
+func (a *ApiService) WithLifecycleGuard(lifecycleGuard bool) *ApiService {
+	a.lifecycleGuard = lifecycleGuard
+	return a
+}
+
+// WithMaxBodySize limits size of gateway response body. Larger bodies fail with ErrBodyTooLarge
+func (a *ApiService) WithMaxBodySize(maxBodySize int64) *ApiService {
+	a.maxBodySize = maxBodySize
+	return a
+}
+
+// WithStrictDecoding makes every method fail with *UnknownFieldsError if the gateway response has fields
+// unknown to the SDK, use it in tests and monitoring to catch changes of the gateway API.
+// The decoded response is returned together with the error
+func (a *ApiService) WithStrictDecoding(strictDecoding bool) *ApiService {
+	a.strictDecoding = strictDecoding
+	return a
+}
+
+// WithRecorder passes every request and its result to recorder
+func (a *ApiService) WithRecorder(recorder Recorder) *ApiService {
+	a.recorder = recorder
+	return a
+}
+
+// Payment fails with *vo.FiscalReceiptError without sending the request if its fiscal receipt is invalid
+func (a ApiService) Payment(ctx context.Context, paymentRequest vo.PaymentRequest) (vo.TransactionResponse, error) {
+	if err := paymentRequest.ValidateFiscalReceipt(); err != nil {
 		return vo.TransactionResponse{}, err
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-
-		}
-	}(resp.Body)
-	var result vo.TransactionResponse
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
+	return execute(ctx, a, paymentEndpoint, paymentRequest)
+}
+
+// Authorizations fails with *vo.FiscalReceiptError without sending the request if its fiscal receipt is invalid
+func (a ApiService) Authorizations(ctx context.Context, authorizationRequest vo.AuthorizationRequest) (vo.TransactionResponse, error) {
+	if err := authorizationRequest.ValidateFiscalReceipt(); err != nil {
 		return vo.TransactionResponse{}, err
 	}
-	return result, nil
+	return execute(ctx, a, authorizationEndpoint, authorizationRequest)
 }
 
 func (a ApiService) Capture(ctx context.Context, captureRequest vo.CaptureRequest) (vo.TransactionResponse, error) {
-	resp, err := a.api.Capture(ctx, captureRequest)
-	if err != nil {
+	if err := a.guard(ctx, captureRequest.Request.ParentUid, vo.OperationCapture); err != nil {
 		return vo.TransactionResponse{}, err
 	}
-	if resp.StatusCode != 200 {
-		return vo.TransactionResponse{}, errors.New(fmt.Sprintf("Error, status code: %d", resp.StatusCode))
+	return a.capture(ctx, captureRequest)
+}
+
+func (a ApiService) capture(ctx context.Context, captureRequest vo.CaptureRequest) (vo.TransactionResponse, error) {
+	return execute(ctx, a, captureEndpoint, captureRequest)
+}
+
+func (a ApiService) Void(ctx context.Context, voidRequest vo.VoidRequest) (vo.TransactionResponse, error) {
+	if err := a.guard(ctx, voidRequest.Request.ParentUid, vo.OperationVoid); err != nil {
+		return vo.TransactionResponse{}, err
 	}
+	return a.void(ctx, voidRequest)
+}
 
-	defer func(Body io.ReadCloser) {
-		err = Body.Close()
-		if err != nil {
-			log.Fatalln(err)
-		}
-	}(resp.Body)
+func (a ApiService) void(ctx context.Context, voidRequest vo.VoidRequest) (vo.TransactionResponse, error) {
+	return execute(ctx, a, voidEndpoint, voidRequest)
+}
 
-	var result vo.TransactionResponse
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
+func (a ApiService) Refund(ctx context.Context, refundRequest vo.RefundRequest) (vo.TransactionResponse, error) {
+	if err := a.guard(ctx, refundRequest.Request.ParentUid, vo.OperationRefund); err != nil {
 		return vo.TransactionResponse{}, err
 	}
-	return result, nil
+	return a.refund(ctx, refundRequest)
+}
+
+func (a ApiService) refund(ctx context.Context, refundRequest vo.RefundRequest) (vo.TransactionResponse, error) {
+	return execute(ctx, a, refundEndpoint, refundRequest)
+}
+
+func (a ApiService) StatusByUid(ctx context.Context, uid string) (vo.TransactionResponse, error) {
+	return execute(ctx, a, statusByUidEndpoint, uid)
+}
+
+func (a ApiService) StatusByTrackingId(ctx context.Context, trackingId string) (vo.TransactionsResponse, error) {
+	return execute(ctx, a, statusByTrackingIdEndpoint, trackingId)
 }
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// AmountPolicy defines what guarded operations do when requested amount exceeds remaining balance
+type AmountPolicy int
+
+const (
+	// RefuseExceeding returns *ExceedsBalanceError without calling the gateway
+	RefuseExceeding AmountPolicy = iota
+
+	// ClampExceeding reduces requested amount to remaining balance.
+	// If nothing remains, *ExceedsBalanceError is returned
+	ClampExceeding
+)
+
+var (
+	ErrExceedsBalance = errors.New("amount exceeds remaining balance")
+
+	// ErrAmountNotPositive is returned by guarded operations with zero or negative amount
+	ErrAmountNotPositive = errors.New("amount must be positive")
+
+	// ErrNoTrackingId means balance can't be computed, children of a transaction are found by its tracking_id
+	ErrNoTrackingId = errors.New("transaction has no tracking_id")
+)
+
+// ExceedsBalanceError is returned by guarded operations. errors.Is(err, ErrExceedsBalance) is true
+type ExceedsBalanceError struct {
+	Operation string
+	ParentUid string
+	Requested int64
+	Remaining int64
+}
+
+func (e *ExceedsBalanceError) Error() string {
+	return fmt.Sprintf("%s of %s: requested %d, remaining %d: %v", e.Operation, e.ParentUid, e.Requested, e.Remaining, ErrExceedsBalance)
+}
+
+func (e *ExceedsBalanceError) Unwrap() error {
+	return ErrExceedsBalance
+}
+
+// NoTrackingIdError is returned by Balance and operations depending on it without the status request
+// of tracking_id. errors.Is(err, ErrNoTrackingId) is true
+type NoTrackingIdError struct {
+	Uid string
+}
+
+func (e *NoTrackingIdError) Error() string {
+	return fmt.Sprintf("balance of %s: %v", e.Uid, ErrNoTrackingId)
+}
+
+func (e *NoTrackingIdError) Unwrap() error {
+	return ErrNoTrackingId
+}
+
+// Balance fetches transaction by uid and all transactions with the same tracking_id
+// and computes captured, voided and refunded amounts.
+//
+// If uid belongs to a capture, balance of its authorization is returned.
+// Transactions created without tracking_id fail with *NoTrackingIdError
+func (a ApiService) Balance(ctx context.Context, uid string) (vo.Balance, error) {
+	parent, err := a.transaction(ctx, uid)
+	if err != nil {
+		return vo.Balance{}, err
+	}
+	return a.balanceOf(ctx, parent)
+}
+
+// balanceOf is Balance of the fetched transaction
+func (a ApiService) balanceOf(ctx context.Context, parent vo.TransactionResponse) (vo.Balance, error) {
+	var err error
+	if parent.IsCapture() {
+		parent, err = a.transaction(ctx, parent.Transaction.ParentUid)
+		if err != nil {
+			return vo.Balance{}, err
+		}
+	}
+
+	trackingId := parent.Transaction.TrackingId
+	if trackingId == "" {
+		return vo.Balance{}, &NoTrackingIdError{Uid: parent.Transaction.Uid}
+	}
+	tr, err := a.StatusByTrackingId(ctx, trackingId)
+	if err != nil {
+		return vo.Balance{}, err
+	}
+	if tr.Response.Message != "" {
+		return vo.Balance{}, fmt.Errorf("status by tracking_id %s: %s", trackingId, tr.Response.Message)
+	}
+
+	return vo.NewBalance(parent.Transaction, tr.Transactions), nil
+}
+
+// CaptureWithinBalance checks remaining capturable amount of the authorization before Capture
+func (a ApiService) CaptureWithinBalance(ctx context.Context, captureRequest vo.CaptureRequest, policy AmountPolicy) (vo.TransactionResponse, error) {
+	b, err := a.parentBalance(ctx, captureRequest.Request.ParentUid, vo.OperationCapture)
+	if err != nil {
+		return vo.TransactionResponse{}, err
+	}
+
+	amount, err := applyPolicy("capture", captureRequest.Request.ParentUid, captureRequest.Request.Amount, b.Capturable(), policy)
+	if err != nil {
+		return vo.TransactionResponse{}, err
+	}
+	captureRequest.Request.Amount = amount
+
+	return a.capture(ctx, captureRequest)
+}
+
+// VoidWithinBalance checks remaining uncaptured amount of the authorization before Void
+func (a ApiService) VoidWithinBalance(ctx context.Context, voidRequest vo.VoidRequest, policy AmountPolicy) (vo.TransactionResponse, error) {
+	b, err := a.parentBalance(ctx, voidRequest.Request.ParentUid, vo.OperationVoid)
+	if err != nil {
+		return vo.TransactionResponse{}, err
+	}
+
+	amount, err := applyPolicy("void", voidRequest.Request.ParentUid, voidRequest.Request.Amount, b.Capturable(), policy)
+	if err != nil {
+		return vo.TransactionResponse{}, err
+	}
+	voidRequest.Request.Amount = amount
+
+	return a.void(ctx, voidRequest)
+}
+
+// RefundWithinBalance checks remaining refundable amount of the payment or authorization before Refund
+func (a ApiService) RefundWithinBalance(ctx context.Context, refundRequest vo.RefundRequest, policy AmountPolicy) (vo.TransactionResponse, error) {
+	b, err := a.parentBalance(ctx, refundRequest.Request.ParentUid, vo.OperationRefund)
+	if err != nil {
+		return vo.TransactionResponse{}, err
+	}
+
+	amount, err := applyPolicy("refund", refundRequest.Request.ParentUid, refundRequest.Request.Amount, b.Refundable(), policy)
+	if err != nil {
+		return vo.TransactionResponse{}, err
+	}
+	refundRequest.Request.Amount = amount
+
+	return a.refund(ctx, refundRequest)
+}
+
+// transaction is StatusByUid which treats error response as error
+func (a ApiService) transaction(ctx context.Context, uid string) (vo.TransactionResponse, error) {
+	tr, err := a.StatusByUid(ctx, uid)
+	if err != nil {
+		return vo.TransactionResponse{}, err
+	}
+	if tr.IsError() {
+		return vo.TransactionResponse{}, fmt.Errorf("status by uid %s: %s", uid, tr.Response.Message)
+	}
+	return tr, nil
+}
+
+func applyPolicy(operation, parentUid string, requested, remaining int64, policy AmountPolicy) (int64, error) {
+	if requested <= 0 {
+		return 0, fmt.Errorf("%s of %s: %w, requested %d", operation, parentUid, ErrAmountNotPositive, requested)
+	}
+	if requested <= remaining {
+		return requested, nil
+	}
+
+	if policy == ClampExceeding && remaining > 0 {
+		return remaining, nil
+	}
+
+	return 0, &ExceedsBalanceError{
+		Operation: operation,
+		ParentUid: parentUid,
+		Requested: requested,
+		Remaining: remaining,
+	}
+}
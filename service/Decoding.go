@@ -0,0 +1,211 @@
+package service
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMaxBodySize is enough for status by tracking_id with hundreds of transactions
+	DefaultMaxBodySize = 10 << 20
+
+	// snippetSize is length of body kept in errors
+	snippetSize = 512
+)
+
+var (
+	ErrUnexpectedStatus = errors.New("unexpected http status")
+	ErrBodyTooLarge     = errors.New("response body is too large")
+	ErrNotJSON          = errors.New("response body is not JSON")
+	ErrUnknownFields    = errors.New("response has unknown fields")
+)
+
+// StatusError is returned for http statuses without gateway response in the body
+type StatusError struct {
+	StatusCode int
+
+	// Snippet is the beginning of the body
+	Snippet string
+}
+
+func (e *StatusError) Error() string {
+	if e.Snippet == "" {
+		return fmt.Sprintf("Error, status code: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("Error, status code: %d: %s", e.StatusCode, e.Snippet)
+}
+
+func (e *StatusError) Unwrap() error {
+	return ErrUnexpectedStatus
+}
+
+// BodyError is returned if the body can't be decoded: it is too large, not JSON or malformed JSON,
+// e.g. HTML error page of a proxy
+type BodyError struct {
+	StatusCode  int
+	ContentType string
+
+	// Snippet is the beginning of the body, truncated to 512 bytes
+	Snippet string
+
+	// Err is ErrBodyTooLarge, ErrNotJSON or JSON decoding error
+	Err error
+}
+
+func (e *BodyError) Error() string {
+	return fmt.Sprintf("bad gateway response, status code: %d, content type: %q: %v: %s", e.StatusCode, e.ContentType, e.Err, e.Snippet)
+}
+
+func (e *BodyError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownFieldsError is returned in strict mode, see ApiService.WithStrictDecoding
+type UnknownFieldsError struct {
+	// Fields are paths like "transaction.new_field"
+	Fields []string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return "response has unknown fields: " + strings.Join(e.Fields, ", ")
+}
+
+func (e *UnknownFieldsError) Unwrap() error {
+	return ErrUnknownFields
+}
+
+// unknownFields is implemented by responses which keep fields unknown to the SDK
+type unknownFields interface {
+	UnknownFields() []string
+}
+
+// execute sends request to endpoint e and decodes the body to Resp.
+//
+// Gateway answers with JSON on success (2xx) and on declined or invalid requests (4xx),
+// the latter are returned as Resp with filled error response and nil error. Other statuses fail with *StatusError.
+// The result is passed to Recorder with the name of e
+func execute[Req, Resp any](ctx context.Context, a ApiService, e endpoint[Req, Resp], request Req) (Resp, error) {
+	var result Resp
+
+	resp, err := e.send(a.api, ctx, request)
+	if err == nil {
+		err = a.decode(resp, &result)
+		resp.Body.Close()
+	}
+
+	if a.recorder != nil {
+		a.recorder.Record(ctx, e.name, request, result, err)
+	}
+	return result, err
+}
+
+func (a ApiService) decode(resp *http.Response, v interface{}) error {
+	limit := a.maxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return err
+	}
+
+	if !hasGatewayBody(resp.StatusCode) {
+		return &StatusError{StatusCode: resp.StatusCode, Snippet: snippet(body)}
+	}
+
+	bodyError := func(err error) error {
+		return &BodyError{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Snippet:     snippet(body),
+			Err:         err,
+		}
+	}
+
+	if int64(len(body)) > limit {
+		return bodyError(ErrBodyTooLarge)
+	}
+	if !isJSON(resp.Header.Get("Content-Type")) {
+		return bodyError(ErrNotJSON)
+	}
+
+	if err = json.Unmarshal(body, v); err != nil {
+		return bodyError(err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		switch r := v.(type) {
+		case *vo.TransactionResponse:
+			r.Response.StatusCode = resp.StatusCode
+		case *vo.TransactionsResponse:
+			r.Response.StatusCode = resp.StatusCode
+		}
+	}
+
+	if a.strictDecoding {
+		if fields := unknown(v); len(fields) > 0 {
+			return &UnknownFieldsError{Fields: fields}
+		}
+	}
+
+	return nil
+}
+
+// unknown returns fields of the response v which are unknown to the SDK at any depth
+func unknown(v interface{}) []string {
+	if u, ok := v.(unknownFields); ok {
+		return u.UnknownFields()
+	}
+	return nil
+}
+
+func hasGatewayBody(statusCode int) bool {
+	switch {
+	case statusCode == http.StatusNoContent:
+		return false
+	case statusCode >= 200 && statusCode < 300:
+		return true
+	case statusCode >= 400 && statusCode < 500:
+		return true
+	}
+	return false
+}
+
+// isJSON allows missing content type, the gateway always sets it, but proxies and tests may not
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	t, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return t == "application/json" || strings.HasSuffix(t, "+json")
+}
+
+func snippet(body []byte) string {
+	if len(body) <= snippetSize {
+		return string(body)
+	}
+
+	// only the rune cut by the limit is dropped, invalid bytes before it are kept as they are
+	b := body[:snippetSize]
+	start := len(b) - 1
+	for start > 0 && len(b)-start < utf8.UTFMax && !utf8.RuneStart(b[start]) {
+		start--
+	}
+	if !utf8.FullRune(b[start:]) {
+		b = b[:start]
+	}
+	return string(b) + "..."
+}
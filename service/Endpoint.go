@@ -0,0 +1,26 @@
+package service
+
+import (
+	"bepaid-sdk/api/contracts"
+	"bepaid-sdk/service/vo"
+	"context"
+	"net/http"
+)
+
+// endpoint describes a gateway operation: request type Req, response type Resp and the method of contracts.Api
+// sending the request. New operations are declared here and executed with execute
+type endpoint[Req, Resp any] struct {
+	// name of the operation passed to Recorder
+	name string
+	send func(api contracts.Api, ctx context.Context, request Req) (*http.Response, error)
+}
+
+var (
+	paymentEndpoint            = endpoint[vo.PaymentRequest, vo.TransactionResponse]{RecordPayment, contracts.Api.Payment}
+	authorizationEndpoint      = endpoint[vo.AuthorizationRequest, vo.TransactionResponse]{RecordAuthorization, contracts.Api.Authorization}
+	captureEndpoint            = endpoint[vo.CaptureRequest, vo.TransactionResponse]{RecordCapture, contracts.Api.Capture}
+	voidEndpoint               = endpoint[vo.VoidRequest, vo.TransactionResponse]{RecordVoid, contracts.Api.Void}
+	refundEndpoint             = endpoint[vo.RefundRequest, vo.TransactionResponse]{RecordRefund, contracts.Api.Refund}
+	statusByUidEndpoint        = endpoint[string, vo.TransactionResponse]{RecordStatusByUid, contracts.Api.StatusByUid}
+	statusByTrackingIdEndpoint = endpoint[string, vo.TransactionsResponse]{RecordStatusByTrackingId, contracts.Api.StatusByTrackingId}
+)
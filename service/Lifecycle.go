@@ -0,0 +1,73 @@
+package service
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrOperationNotAllowed = errors.New("operation is not allowed in current transaction state")
+
+// OperationNotAllowedError is returned by guarded operations. errors.Is(err, ErrOperationNotAllowed) is true
+type OperationNotAllowedError struct {
+	Operation vo.Operation
+	ParentUid string
+	State     vo.State
+
+	// ParentType is set if the parent has wrong type, e.g. capture of a capture
+	ParentType string
+}
+
+func (e *OperationNotAllowedError) Error() string {
+	if e.ParentType != "" {
+		return fmt.Sprintf("%s of %s of type %q: %v", e.Operation, e.ParentUid, e.ParentType, ErrOperationNotAllowed)
+	}
+	return fmt.Sprintf("%s of %s in state %q: %v", e.Operation, e.ParentUid, e.State, ErrOperationNotAllowed)
+}
+
+func (e *OperationNotAllowedError) Unwrap() error {
+	return ErrOperationNotAllowed
+}
+
+// guard checks that operation is allowed for parentUid if lifecycle guard is enabled
+func (a ApiService) guard(ctx context.Context, parentUid string, operation vo.Operation) error {
+	if !a.lifecycleGuard {
+		return nil
+	}
+
+	_, err := a.parentBalance(ctx, parentUid, operation)
+	return err
+}
+
+// parentBalance returns balance of parentUid. Captures and voids of anything but authorization are refused.
+// If lifecycle guard is enabled, operation must be allowed in the state of the balance
+func (a ApiService) parentBalance(ctx context.Context, parentUid string, operation vo.Operation) (vo.Balance, error) {
+	parent, err := a.transaction(ctx, parentUid)
+	if err != nil {
+		return vo.Balance{}, err
+	}
+
+	// Balance walks up from a capture to its authorization, so the type is checked before
+	if (operation == vo.OperationCapture || operation == vo.OperationVoid) && !parent.IsAuthorization() {
+		return vo.Balance{}, &OperationNotAllowedError{Operation: operation, ParentUid: parentUid, ParentType: parent.Transaction.Type}
+	}
+
+	b, err := a.balanceOf(ctx, parent)
+	if err != nil {
+		return vo.Balance{}, err
+	}
+
+	if a.lifecycleGuard && !b.Allows(operation) {
+		return vo.Balance{}, notAllowed(operation, parentUid, b)
+	}
+	return b, nil
+}
+
+func notAllowed(operation vo.Operation, parentUid string, b vo.Balance) error {
+	return &OperationNotAllowedError{
+		Operation: operation,
+		ParentUid: parentUid,
+		State:     b.State(),
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultPollInitialInterval = time.Second
+	defaultPollMaxInterval     = 30 * time.Second
+	defaultPollMultiplier      = 2
+)
+
+// Clock abstracts time for polling, so tests don't have to sleep
+type Clock interface {
+	// NewTimer returns a channel which fires after d and stop releasing the timer, like time.NewTimer
+	NewTimer(d time.Duration) (c <-chan time.Time, stop func() bool)
+}
+
+type realClock struct{}
+
+func (realClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	t := time.NewTimer(d)
+	return t.C, t.Stop
+}
+
+// WaitOptions configures WaitForFinalStatus. Zero value is valid
+type WaitOptions struct {
+	// InitialInterval is the delay before the second status request. Default is 1s
+	InitialInterval time.Duration
+
+	// MaxInterval limits the delay between status requests. Default is 30s
+	MaxInterval time.Duration
+
+	// Multiplier increases the delay after every status request. Default is 2
+	Multiplier float64
+
+	// Notifier delivers transactions received by webhook.
+	// Final transaction with the awaited uid is returned immediately,
+	// incomplete one triggers status request without waiting for the delay
+	Notifier <-chan vo.TransactionResponse
+
+	// Clock is used for delays. Default is real time
+	Clock Clock
+}
+
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.InitialInterval <= 0 {
+		o.InitialInterval = defaultPollInitialInterval
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = defaultPollMaxInterval
+	}
+	if o.MaxInterval < o.InitialInterval {
+		o.MaxInterval = o.InitialInterval
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = defaultPollMultiplier
+	}
+	if o.Clock == nil {
+		o.Clock = realClock{}
+	}
+	return o
+}
+
+// WaitForFinalStatus requests status of the transaction until it is not incomplete.
+//
+// Error response of the gateway and transport errors stop polling.
+// If ctx is done, the last received response is returned with ctx.Err()
+func (a ApiService) WaitForFinalStatus(ctx context.Context, uid string, options WaitOptions) (vo.TransactionResponse, error) {
+	options = options.withDefaults()
+	interval := options.InitialInterval
+
+	for {
+		tr, err := a.StatusByUid(ctx, uid)
+		if err != nil {
+			return tr, err
+		}
+		if tr.IsError() {
+			return tr, fmt.Errorf("status by uid %s: %s", uid, tr.Response.Message)
+		}
+		if !tr.IsIncomplete() {
+			return tr, nil
+		}
+
+		notified, err := a.waitNext(ctx, uid, interval, options)
+		if err != nil {
+			return tr, err
+		}
+		if notified != nil {
+			return *notified, nil
+		}
+
+		interval = time.Duration(float64(interval) * options.Multiplier)
+		if interval > options.MaxInterval {
+			interval = options.MaxInterval
+		}
+	}
+}
+
+// waitNext waits for interval, webhook or ctx cancellation.
+// It returns not nil transaction only if webhook with final status of uid arrived
+func (a ApiService) waitNext(ctx context.Context, uid string, interval time.Duration, options WaitOptions) (*vo.TransactionResponse, error) {
+	timer, stop := options.Clock.NewTimer(interval)
+	defer stop()
+
+	notifier := options.Notifier
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer:
+			return nil, nil
+		case tr, ok := <-notifier:
+			if !ok {
+				// closed notifier blocks forever
+				notifier = nil
+				continue
+			}
+			if tr.Transaction.Uid != uid {
+				continue
+			}
+			if tr.IsIncomplete() {
+				return nil, nil
+			}
+			return &tr, nil
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -43,7 +44,7 @@ func TestApiService_Capture(t *testing.T) {
 	r := ioutil.NopCloser(bytes.NewReader([]byte(json_req_1)))
 	capture := testdata.NewMockApi(ctrl)
 
-	capture.EXPECT().Capture(context.Background(), *vo.NewCaptureRequest(50, "1-310b0da80b")).Return(http.Response{
+	capture.EXPECT().Capture(context.Background(), *vo.NewCaptureRequest(50, "1-310b0da80b")).Return(&http.Response{
 		StatusCode: 200,
 		Body:       r,
 	}, nil)
@@ -54,7 +55,7 @@ func TestApiService_Capture(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, response)
 	assert.Equal(t, "successful", response.Transaction.Status)
-	assert.Equal(t, 50, response.Transaction.Amount)
+	assert.Equal(t, int64(50), response.Transaction.Amount)
 	assert.Equal(t, "1-310b0da80b", response.Transaction.ParentUid)
 }
 
@@ -65,7 +66,7 @@ func TestApiService_Capture2(t *testing.T) {
 	r := ioutil.NopCloser(bytes.NewReader([]byte(json_req_1)))
 	capture := testdata.NewMockApi(ctrl)
 
-	capture.EXPECT().Capture(context.Background(), *vo.NewCaptureRequest(50, "1-310b0da80b")).Return(http.Response{
+	capture.EXPECT().Capture(context.Background(), *vo.NewCaptureRequest(50, "1-310b0da80b")).Return(&http.Response{
 		StatusCode: 200,
 		Body:       r,
 	}, errors.New("error message"))
@@ -84,7 +85,7 @@ func TestApiService_Capture3(t *testing.T) {
 	r := ioutil.NopCloser(bytes.NewReader([]byte(json_req_1)))
 	capture := testdata.NewMockApi(ctrl)
 
-	capture.EXPECT().Capture(context.Background(), *vo.NewCaptureRequest(50, "1-310b0da80b")).Return(http.Response{
+	capture.EXPECT().Capture(context.Background(), *vo.NewCaptureRequest(50, "1-310b0da80b")).Return(&http.Response{
 		StatusCode: 100,
 		Body:       r,
 	}, nil)
@@ -93,5 +94,57 @@ func TestApiService_Capture3(t *testing.T) {
 	_, err := captureTest.Capture(context.Background(), *vo.NewCaptureRequest(50, "1-310b0da80b"))
 
 	assert.NotNil(t, err)
-	assert.Equal(t, "Error, status code: 100", err.Error())
+	assert.True(t, strings.HasPrefix(err.Error(), "Error, status code: 100: {"))
+}
+
+func TestApiService_InvalidFiscalReceiptIsNotSent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no calls are expected
+	s := NewApiService(testdata.NewMockApi(ctrl))
+	receipt := *vo.NewFiscalReceipt(*vo.NewFiscalItem("Яблоки", 1, 399, vo.TaxVat10))
+
+	_, err := s.Payment(context.Background(), *vo.NewPaymentRequest(1000, "BYN", "order", "order-1", false, vo.CreditCard{}).WithFiscalReceipt(receipt))
+	var receiptErr *vo.FiscalReceiptError
+	assert.ErrorAs(t, err, &receiptErr)
+
+	_, err = s.Authorizations(context.Background(), *vo.NewAuthorizationRequest(1000, "BYN", "order", "order-1", false, vo.CreditCard{}).WithFiscalReceipt(receipt))
+	assert.ErrorIs(t, err, vo.ErrFiscalReceipt)
+}
+
+func TestApiService_GatewayErrorBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().Void(gomock.Any(), gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusUnprocessableEntity,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"response":{"message":"Amount can't be blank","errors":{"amount":["can't be blank"]}}}`))),
+	}, nil)
+
+	response, err := NewApiService(api).Void(context.Background(), *vo.NewVoidRequest("1-310b0da80b", 0))
+
+	assert.Nil(t, err)
+	assert.True(t, response.IsError())
+	assert.Equal(t, "Amount can't be blank", response.Response.Message)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Response.StatusCode)
+}
+
+func TestApiService_UnexpectedStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(&http.Response{
+		StatusCode: http.StatusBadGateway,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(`<html>Bad Gateway</html>`))),
+	}, nil)
+
+	_, err := NewApiService(api).StatusByTrackingId(context.Background(), "order-1")
+
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.True(t, errors.Is(err, ErrUnexpectedStatus))
+	assert.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
 }
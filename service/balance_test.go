@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bepaid-sdk/service/vo"
+	"bepaid-sdk/testdata"
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	authorizationStatus = `{"transaction":{"uid":"1-310b0da80b","status":"successful","amount":100,"currency":"USD","type":"authorization","tracking_id":"order-1"}}`
+
+	captureStatus = `{"transaction":{"uid":"2-310b0da80b","status":"successful","amount":60,"currency":"USD","type":"capture","parent_uid":"1-310b0da80b","tracking_id":"order-1"}}`
+
+	orderTransactions = `{"transactions":[
+		{"uid":"1-310b0da80b","status":"successful","amount":100,"currency":"USD","type":"authorization","tracking_id":"order-1"},
+		{"uid":"2-310b0da80b","status":"successful","amount":60,"currency":"USD","type":"capture","parent_uid":"1-310b0da80b","tracking_id":"order-1"},
+		{"uid":"3-310b0da80b","status":"failed","amount":40,"currency":"USD","type":"capture","parent_uid":"1-310b0da80b","tracking_id":"order-1"},
+		{"uid":"4-310b0da80b","status":"successful","amount":25,"currency":"USD","type":"refund","parent_uid":"2-310b0da80b","tracking_id":"order-1"},
+		{"uid":"5-310b0da80b","status":"successful","amount":10,"currency":"USD","type":"refund","parent_uid":"9-000000000","tracking_id":"order-1"}
+	]}`
+)
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+func expectOrderStatus(api *testdata.MockApi, uid, body string) {
+	api.EXPECT().StatusByUid(gomock.Any(), uid).Return(jsonResponse(body), nil)
+	api.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(jsonResponse(orderTransactions), nil)
+}
+
+func TestApiService_Balance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	expectOrderStatus(api, "1-310b0da80b", authorizationStatus)
+
+	b, err := NewApiService(api).Balance(context.Background(), "1-310b0da80b")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "1-310b0da80b", b.Uid)
+	assert.Equal(t, int64(100), b.Amount)
+	assert.Equal(t, int64(60), b.Captured)
+	assert.Equal(t, int64(25), b.Refunded)
+	assert.Equal(t, int64(40), b.Capturable())
+	assert.Equal(t, int64(35), b.Refundable())
+}
+
+func TestApiService_BalanceOfCapture(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "2-310b0da80b").Return(jsonResponse(captureStatus), nil)
+	expectOrderStatus(api, "1-310b0da80b", authorizationStatus)
+
+	b, err := NewApiService(api).Balance(context.Background(), "2-310b0da80b")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "1-310b0da80b", b.Uid)
+	assert.Equal(t, int64(35), b.Refundable())
+}
+
+func TestApiService_BalanceStatusError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(`{"response":{"message":"Record not found"}}`), nil)
+
+	_, err := NewApiService(api).Balance(context.Background(), "1-310b0da80b")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "status by uid 1-310b0da80b: Record not found", err.Error())
+}
+
+func TestApiService_CaptureWithinBalanceRefuse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	expectOrderStatus(api, "1-310b0da80b", authorizationStatus)
+
+	_, err := NewApiService(api).CaptureWithinBalance(context.Background(), *vo.NewCaptureRequest(50, "1-310b0da80b"), RefuseExceeding)
+
+	assert.True(t, errors.Is(err, ErrExceedsBalance))
+	var e *ExceedsBalanceError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, int64(50), e.Requested)
+		assert.Equal(t, int64(40), e.Remaining)
+	}
+}
+
+func TestApiService_CaptureWithinBalanceClamp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	expectOrderStatus(api, "1-310b0da80b", authorizationStatus)
+	api.EXPECT().Capture(gomock.Any(), *vo.NewCaptureRequest(40, "1-310b0da80b")).Return(jsonResponse(json_req_1), nil)
+
+	_, err := NewApiService(api).CaptureWithinBalance(context.Background(), *vo.NewCaptureRequest(50, "1-310b0da80b"), ClampExceeding)
+
+	assert.Nil(t, err)
+}
+
+func TestApiService_RefundWithinBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	for i := 0; i < 2; i++ {
+		api.EXPECT().StatusByUid(gomock.Any(), "2-310b0da80b").Return(jsonResponse(captureStatus), nil)
+		expectOrderStatus(api, "1-310b0da80b", authorizationStatus)
+	}
+	api.EXPECT().Refund(gomock.Any(), *vo.NewRefundRequest("2-310b0da80b", 35, "reason")).Return(jsonResponse(`{"transaction":{"status":"successful"}}`), nil)
+
+	s := NewApiService(api)
+
+	_, err := s.RefundWithinBalance(context.Background(), *vo.NewRefundRequest("2-310b0da80b", 36, "reason"), RefuseExceeding)
+	assert.True(t, errors.Is(err, ErrExceedsBalance))
+
+	_, err = s.RefundWithinBalance(context.Background(), *vo.NewRefundRequest("2-310b0da80b", 35, "reason"), RefuseExceeding)
+	assert.Nil(t, err)
+}
+
+func TestApiService_VoidWithinBalanceNothingLeft(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(authorizationStatus), nil)
+	api.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(jsonResponse(`{"transactions":[
+		{"uid":"2-310b0da80b","status":"successful","amount":100,"type":"capture","parent_uid":"1-310b0da80b"}
+	]}`), nil)
+
+	_, err := NewApiService(api).VoidWithinBalance(context.Background(), *vo.NewVoidRequest("1-310b0da80b", 100), ClampExceeding)
+
+	assert.True(t, errors.Is(err, ErrExceedsBalance))
+}
+
+func TestApiService_BalanceWithoutTrackingId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(
+		`{"transaction":{"uid":"1-310b0da80b","status":"successful","amount":100,"currency":"USD","type":"authorization"}}`), nil)
+
+	_, err := NewApiService(api).CaptureWithinBalance(context.Background(), *vo.NewCaptureRequest(50, "1-310b0da80b"), RefuseExceeding)
+
+	assert.True(t, errors.Is(err, ErrNoTrackingId))
+	var e *NoTrackingIdError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, "1-310b0da80b", e.Uid)
+	}
+}
+
+func TestApiService_RefundWithinBalanceNotPositive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	for i := 0; i < 2; i++ {
+		expectOrderStatus(api, "1-310b0da80b", authorizationStatus)
+	}
+	s := NewApiService(api)
+
+	for _, amount := range []int64{0, -10} {
+		_, err := s.RefundWithinBalance(context.Background(), *vo.NewRefundRequest("1-310b0da80b", amount, "reason"), ClampExceeding)
+		assert.True(t, errors.Is(err, ErrAmountNotPositive))
+	}
+}
@@ -0,0 +1,101 @@
+package batch
+
+import (
+	"bepaid-sdk/internal/jsonfile"
+	"bepaid-sdk/service/vo"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Checkpoint keeps responses of completed operations, so an interrupted batch can be run again
+// without repeating them. Only operations with successful or final transaction are saved, operations failed
+// with error or gateway error response are run again on the next run, see Runner.WithIdempotencyStore
+type Checkpoint interface {
+	Load(ctx context.Context, id string) (vo.TransactionResponse, bool, error)
+	Save(ctx context.Context, id string, response vo.TransactionResponse) error
+}
+
+type MemoryCheckpoint struct {
+	mu        sync.Mutex
+	responses map[string]vo.TransactionResponse
+}
+
+func NewMemoryCheckpoint() *MemoryCheckpoint {
+	return &MemoryCheckpoint{responses: map[string]vo.TransactionResponse{}}
+}
+
+func (c *MemoryCheckpoint) Load(_ context.Context, id string) (vo.TransactionResponse, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, ok := c.responses[id]
+	return r, ok, nil
+}
+
+func (c *MemoryCheckpoint) Save(_ context.Context, id string, response vo.TransactionResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.responses[id] = response
+	return nil
+}
+
+// FileCheckpoint appends every completed operation as a JSON line to the file and syncs it
+type FileCheckpoint struct {
+	mu        sync.Mutex
+	lines     *jsonfile.Lines
+	responses map[string]vo.TransactionResponse
+}
+
+type checkpointLine struct {
+	Id       string                 `json:"id"`
+	Response vo.TransactionResponse `json:"response"`
+}
+
+// OpenFileCheckpoint reads operations completed in previous runs and opens the file for appending.
+// A partially written last line, e.g. after a crash, is removed
+func OpenFileCheckpoint(path string) (*FileCheckpoint, error) {
+	lines, complete, err := jsonfile.OpenLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &FileCheckpoint{lines: lines, responses: map[string]vo.TransactionResponse{}}
+
+	for n, line := range complete {
+		var l checkpointLine
+		if err = json.Unmarshal(line, &l); err != nil {
+			lines.Close()
+			return nil, fmt.Errorf("checkpoint %s:%d: %w", path, n+1, err)
+		}
+		c.responses[l.Id] = l.Response
+	}
+
+	return c, nil
+}
+
+func (c *FileCheckpoint) Load(_ context.Context, id string) (vo.TransactionResponse, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, ok := c.responses[id]
+	return r, ok, nil
+}
+
+func (c *FileCheckpoint) Save(_ context.Context, id string, response vo.TransactionResponse) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.lines.Append(checkpointLine{Id: id, Response: response}); err != nil {
+		return err
+	}
+
+	c.responses[id] = response
+	return nil
+}
+
+func (c *FileCheckpoint) Close() error {
+	return c.lines.Close()
+}
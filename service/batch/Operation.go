@@ -0,0 +1,65 @@
+package batch
+
+import (
+	"bepaid-sdk/service/vo"
+	"errors"
+)
+
+type Kind string
+
+const (
+	KindCapture Kind = "capture"
+	KindVoid    Kind = "void"
+	KindRefund  Kind = "refund"
+	KindStatus  Kind = "status"
+)
+
+var (
+	ErrEmptyId       = errors.New("batch: operation id is empty")
+	ErrDuplicateId   = errors.New("batch: duplicate operation id")
+	ErrUnknownKind   = errors.New("batch: unknown operation kind")
+	ErrNotDispatched = errors.New("batch: operation is not dispatched")
+)
+
+// Operation is one item of a batch
+type Operation struct {
+	// Id identifies the operation in checkpoints, e.g. id of refund in the local system. Must be unique in the batch
+	Id string `json:"id"`
+
+	Kind Kind `json:"kind"`
+
+	// Uid is parent uid for capture, void and refund and uid of the transaction for status
+	Uid string `json:"uid"`
+
+	//сумма в минимальных денежных единицах, не используется для status
+	Amount int64 `json:"amount,omitempty"`
+
+	//причина возврата, обязательна для refund
+	Reason string `json:"reason,omitempty"`
+}
+
+// Result of one operation. Err is set if the gateway response was not received, declines are in Response
+type Result struct {
+	// Index of the operation in the input
+	Index int
+
+	Operation Operation
+	Response  vo.TransactionResponse
+	Err       error
+
+	// Skipped is true if the operation was completed in a previous run, Response is taken from Checkpoint
+	Skipped bool
+}
+
+// Failed is true if the gateway response was not received or it is an error or failed transaction
+func (r Result) Failed() bool {
+	return r.Err != nil || r.Response.IsError() || r.Response.IsFailed()
+}
+
+// Progress of a batch. Total is zero if operations are read from a channel
+type Progress struct {
+	Total     int
+	Completed int
+	Failed    int
+	Skipped   int
+}
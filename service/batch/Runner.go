@@ -0,0 +1,266 @@
+package batch
+
+import (
+	"bepaid-sdk/api/contracts"
+	"bepaid-sdk/service"
+	"bepaid-sdk/service/idempotency"
+	"bepaid-sdk/service/vo"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const defaultWorkers = 4
+
+// Runner executes batches of captures, voids, refunds and status requests.
+//
+// Captures, voids and refunds go through idempotency.Executor keyed by operation id: if the outcome of a request
+// is unknown, e.g. timeout, the transaction is looked up by tracking_id instead of sending the request again
+type Runner struct {
+	service    *service.ApiService
+	executor   *idempotency.Executor
+	workers    int
+	interval   time.Duration
+	checkpoint Checkpoint
+	progress   func(Progress, Result)
+}
+
+func NewRunner(api contracts.Api) *Runner {
+	s := service.NewApiService(api)
+	return &Runner{
+		service:  s,
+		executor: idempotency.NewExecutor(s, idempotency.NewMemoryStore()),
+		workers:  defaultWorkers,
+	}
+}
+
+// WithIdempotencyStore keeps records of captures, voids and refunds between runs. Without it operations
+// with unknown outcome are looked up within the run only, the next run sends them again.
+// Use it together with WithCheckpoint, e.g. idempotency.NewFileStore next to the checkpoint file
+func (r *Runner) WithIdempotencyStore(store idempotency.Store) *Runner {
+	r.executor = idempotency.NewExecutor(r.service, store)
+	return r
+}
+
+// WithWorkers sets the number of concurrent requests. Default is 4
+func (r *Runner) WithWorkers(workers int) *Runner {
+	if workers < 1 {
+		workers = 1
+	}
+	r.workers = workers
+	return r
+}
+
+// WithRateLimit allows at most requests per period, e.g. WithRateLimit(10, time.Second). Default is no limit
+func (r *Runner) WithRateLimit(requests int, per time.Duration) *Runner {
+	r.interval = 0
+	if requests > 0 && per > 0 {
+		r.interval = per / time.Duration(requests)
+	}
+	return r
+}
+
+// WithCheckpoint skips operations completed in previous runs and saves completed ones.
+// Operations with gateway error response or incomplete transaction are not saved and run again
+func (r *Runner) WithCheckpoint(checkpoint Checkpoint) *Runner {
+	r.checkpoint = checkpoint
+	return r
+}
+
+// WithProgress calls progress after every operation with its result. Calls are never concurrent
+func (r *Runner) WithProgress(progress func(Progress, Result)) *Runner {
+	r.progress = progress
+	return r
+}
+
+// RunSlice executes operations and returns results in the same order.
+//
+// If ctx is done, operations which were not started have ErrNotDispatched and ctx.Err() is returned
+func (r *Runner) RunSlice(ctx context.Context, operations []Operation) ([]Result, error) {
+	ch := make(chan Operation)
+	go func() {
+		defer close(ch)
+		for _, o := range operations {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- o:
+			}
+		}
+	}()
+
+	results, err := r.run(ctx, ch, len(operations))
+
+	all := make([]Result, len(operations))
+	for i, o := range operations {
+		all[i] = Result{Index: i, Operation: o, Err: ErrNotDispatched}
+	}
+	for _, res := range results {
+		all[res.Index] = res
+	}
+
+	return all, err
+}
+
+// Run reads operations until the channel is closed and returns results ordered by Result.Index.
+//
+// If ctx is done, results of already started operations are returned with ctx.Err()
+func (r *Runner) Run(ctx context.Context, operations <-chan Operation) ([]Result, error) {
+	return r.run(ctx, operations, 0)
+}
+
+type job struct {
+	index     int
+	operation Operation
+}
+
+func (r *Runner) run(ctx context.Context, operations <-chan Operation, total int) ([]Result, error) {
+	var limit <-chan time.Time
+	if r.interval > 0 {
+		t := time.NewTicker(r.interval)
+		defer t.Stop()
+		limit = t.C
+	}
+
+	jobs := make(chan job)
+	results := make(chan Result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < r.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results <- r.execute(ctx, j, limit)
+			}
+		}()
+	}
+
+	var all []Result
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p := Progress{Total: total}
+		for res := range results {
+			all = append(all, res)
+
+			p.Completed++
+			if res.Skipped {
+				p.Skipped++
+			}
+			if res.Failed() {
+				p.Failed++
+			}
+			if r.progress != nil {
+				r.progress(p, res)
+			}
+		}
+	}()
+
+	err := r.dispatch(ctx, operations, jobs, results)
+
+	close(jobs)
+	wg.Wait()
+	close(results)
+	<-done
+
+	sort.Slice(all, func(i, j int) bool { return all[i].Index < all[j].Index })
+	return all, err
+}
+
+// dispatch sends operations to workers. Operations without id or with already seen id fail without request
+func (r *Runner) dispatch(ctx context.Context, operations <-chan Operation, jobs chan<- job, results chan<- Result) error {
+	seen := map[string]struct{}{}
+
+	for i := 0; ; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case o, ok := <-operations:
+			if !ok {
+				// RunSlice closes operations when ctx is done
+				return ctx.Err()
+			}
+
+			if o.Id == "" {
+				results <- Result{Index: i, Operation: o, Err: ErrEmptyId}
+				continue
+			}
+			if _, ok := seen[o.Id]; ok {
+				results <- Result{Index: i, Operation: o, Err: ErrDuplicateId}
+				continue
+			}
+			seen[o.Id] = struct{}{}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case jobs <- job{index: i, operation: o}:
+			}
+		}
+	}
+}
+
+func (r *Runner) execute(ctx context.Context, j job, limit <-chan time.Time) Result {
+	res := Result{Index: j.index, Operation: j.operation}
+
+	if res.Err = ctx.Err(); res.Err != nil {
+		return res
+	}
+
+	if r.checkpoint != nil {
+		response, ok, err := r.checkpoint.Load(ctx, j.operation.Id)
+		if err != nil {
+			res.Err = fmt.Errorf("checkpoint: %w", err)
+			return res
+		}
+		if ok {
+			res.Response, res.Skipped = response, true
+			return res
+		}
+	}
+
+	if limit != nil {
+		select {
+		case <-ctx.Done():
+			res.Err = ctx.Err()
+			return res
+		case <-limit:
+		}
+	}
+
+	res.Response, res.Err = r.send(ctx, j.operation)
+	if res.Err != nil || r.checkpoint == nil || !completed(res.Response) {
+		return res
+	}
+
+	if err := r.checkpoint.Save(ctx, j.operation.Id, res.Response); err != nil {
+		res.Err = fmt.Errorf("checkpoint: %w", err)
+	}
+	return res
+}
+
+// completed is true for successful and final transactions. Error responses, e.g. gateway is busy, are failed results
+// and the operation is run again on the next run
+func completed(response vo.TransactionResponse) bool {
+	return !response.IsError() && !response.IsIncomplete()
+}
+
+func (r *Runner) send(ctx context.Context, o Operation) (vo.TransactionResponse, error) {
+	switch o.Kind {
+	case KindCapture:
+		capture := vo.CaptureRequest{}
+		capture.Request.ParentUid = o.Uid
+		capture.Request.Amount = o.Amount
+		return r.executor.Capture(ctx, o.Id, capture)
+	case KindVoid:
+		return r.executor.Void(ctx, o.Id, *vo.NewVoidRequest(o.Uid, o.Amount))
+	case KindRefund:
+		return r.executor.Refund(ctx, o.Id, *vo.NewRefundRequest(o.Uid, o.Amount, o.Reason))
+	case KindStatus:
+		return r.service.StatusByUid(ctx, o.Uid)
+	}
+	return vo.TransactionResponse{}, fmt.Errorf("%w %q", ErrUnknownKind, o.Kind)
+}
@@ -0,0 +1,57 @@
+package batch
+
+import (
+	"bepaid-sdk/service/vo"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "refunds.jsonl")
+
+	c, err := OpenFileCheckpoint(path)
+	assert.NoError(t, err)
+	assert.NoError(t, c.Save(ctx, "r1", vo.TransactionResponse{Transaction: vo.Transaction{Uid: "3-a", Status: "successful"}}))
+	assert.NoError(t, c.Close())
+
+	// crash in the middle of the second line
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	assert.NoError(t, err)
+	_, err = f.WriteString(`{"id":"r2","respo`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	c, err = OpenFileCheckpoint(path)
+	assert.NoError(t, err)
+	defer c.Close()
+
+	r, ok, err := c.Load(ctx, "r1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "3-a", r.Transaction.Uid)
+
+	_, ok, _ = c.Load(ctx, "r2")
+	assert.False(t, ok)
+
+	assert.NoError(t, c.Save(ctx, "r2", vo.TransactionResponse{Transaction: vo.Transaction{Uid: "3-b"}}))
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, bytes.Count(b, []byte{'\n'}))
+}
+
+func TestFileCheckpoint_Corrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "refunds.jsonl")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("not json\n"), 0600))
+
+	_, err := OpenFileCheckpoint(path)
+
+	assert.ErrorContains(t, err, "refunds.jsonl:1")
+}
@@ -0,0 +1,269 @@
+package batch
+
+import (
+	"bepaid-sdk/service/idempotency"
+	"bepaid-sdk/service/vo"
+	"bepaid-sdk/testdata"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+func transaction(uid, typ, status string) *http.Response {
+	return jsonResponse(fmt.Sprintf(`{"transaction":{"uid":%q,"type":%q,"status":%q}}`, uid, typ, status))
+}
+
+// parent is returned to idempotency.Executor before capture, void and refund
+func parent(uid, trackingId string) *http.Response {
+	return jsonResponse(fmt.Sprintf(`{"transaction":{"uid":%q,"type":"authorization","status":"successful","tracking_id":%q}}`, uid, trackingId))
+}
+
+func transactions(list ...string) *http.Response {
+	return jsonResponse(fmt.Sprintf(`{"transactions":[%s]}`, strings.Join(list, ",")))
+}
+
+func TestRunner_RunSlice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	for _, uid := range []string{"1-a", "1-b", "1-c"} {
+		api.EXPECT().StatusByUid(gomock.Any(), uid).Return(parent(uid, ""), nil)
+	}
+	api.EXPECT().Capture(gomock.Any(), *vo.NewCaptureRequest(100, "1-a")).Return(transaction("2-a", "capture", "successful"), nil)
+	api.EXPECT().Void(gomock.Any(), *vo.NewVoidRequest("1-b", 50)).Return(transaction("2-b", "void", "failed"), nil)
+	api.EXPECT().Refund(gomock.Any(), *vo.NewRefundRequest("1-c", 10, "return")).Return(nil, errors.New("connection reset"))
+	api.EXPECT().StatusByUid(gomock.Any(), "1-d").Return(transaction("1-d", "payment", "successful"), nil)
+
+	var progress []Progress
+	runner := NewRunner(api).WithWorkers(2).WithProgress(func(p Progress, _ Result) {
+		progress = append(progress, p)
+	})
+
+	results, err := runner.RunSlice(context.Background(), []Operation{
+		{Id: "c1", Kind: KindCapture, Uid: "1-a", Amount: 100},
+		{Id: "v1", Kind: KindVoid, Uid: "1-b", Amount: 50},
+		{Id: "r1", Kind: KindRefund, Uid: "1-c", Amount: 10, Reason: "return"},
+		{Id: "s1", Kind: KindStatus, Uid: "1-d"},
+		{Id: "s1", Kind: KindStatus, Uid: "1-d"},
+		{Kind: KindStatus, Uid: "1-e"},
+		{Id: "x1", Kind: "credit", Uid: "1-f"},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 7)
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+	}
+
+	assert.Equal(t, "2-a", results[0].Response.Transaction.Uid)
+	assert.False(t, results[0].Failed())
+	assert.True(t, results[1].Failed())
+	assert.EqualError(t, results[2].Err, "connection reset")
+	assert.Equal(t, "1-d", results[3].Response.Transaction.Uid)
+	assert.ErrorIs(t, results[4].Err, ErrDuplicateId)
+	assert.ErrorIs(t, results[5].Err, ErrEmptyId)
+	assert.ErrorIs(t, results[6].Err, ErrUnknownKind)
+
+	assert.Len(t, progress, 7)
+	assert.Equal(t, Progress{Total: 7, Completed: 7, Failed: 5}, progress[6])
+}
+
+func TestRunner_ResumesFromCheckpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkpoint := NewMemoryCheckpoint()
+	assert.NoError(t, checkpoint.Save(context.Background(), "r1", vo.TransactionResponse{Transaction: vo.Transaction{Uid: "3-a"}}))
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-b").Return(parent("1-b", ""), nil)
+	api.EXPECT().Refund(gomock.Any(), *vo.NewRefundRequest("1-b", 10, "return")).Return(nil, errors.New("timeout"))
+	api.EXPECT().StatusByUid(gomock.Any(), "1-c").Return(parent("1-c", ""), nil)
+	api.EXPECT().Refund(gomock.Any(), *vo.NewRefundRequest("1-c", 10, "return")).Return(transaction("3-c", "refund", "successful"), nil)
+
+	operations := []Operation{
+		{Id: "r1", Kind: KindRefund, Uid: "1-a", Amount: 10, Reason: "return"},
+		{Id: "r2", Kind: KindRefund, Uid: "1-b", Amount: 10, Reason: "return"},
+		{Id: "r3", Kind: KindRefund, Uid: "1-c", Amount: 10, Reason: "return"},
+	}
+
+	results, err := NewRunner(api).WithWorkers(1).WithCheckpoint(checkpoint).RunSlice(context.Background(), operations)
+
+	assert.NoError(t, err)
+	assert.True(t, results[0].Skipped)
+	assert.Equal(t, "3-a", results[0].Response.Transaction.Uid)
+	assert.Error(t, results[1].Err)
+	assert.Equal(t, "3-c", results[2].Response.Transaction.Uid)
+
+	_, ok, _ := checkpoint.Load(context.Background(), "r2")
+	assert.False(t, ok, "operations without gateway response must be repeated")
+	_, ok, _ = checkpoint.Load(context.Background(), "r3")
+	assert.True(t, ok)
+}
+
+func TestRunner_ErrorResponseIsNotCheckpointed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	refund := *vo.NewRefundRequest("1-a", 10, "return")
+
+	api := testdata.NewMockApi(ctrl)
+	gomock.InOrder(
+		api.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(parent("1-a", ""), nil),
+		api.EXPECT().Refund(gomock.Any(), refund).Return(jsonResponse(`{"response":{"message":"Gateway is busy"}}`), nil),
+		api.EXPECT().StatusByUid(gomock.Any(), "1-b").Return(transaction("1-b", "payment", "incomplete"), nil),
+
+		// next run sends them again
+		api.EXPECT().Refund(gomock.Any(), refund).Return(transaction("2-a", "refund", "successful"), nil),
+		api.EXPECT().StatusByUid(gomock.Any(), "1-b").Return(transaction("1-b", "payment", "failed"), nil),
+	)
+
+	checkpoint := NewMemoryCheckpoint()
+	store := idempotency.NewMemoryStore()
+	operations := []Operation{
+		{Id: "r1", Kind: KindRefund, Uid: "1-a", Amount: 10, Reason: "return"},
+		{Id: "s1", Kind: KindStatus, Uid: "1-b"},
+	}
+
+	results, err := NewRunner(api).WithWorkers(1).WithCheckpoint(checkpoint).WithIdempotencyStore(store).RunSlice(context.Background(), operations)
+	assert.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.True(t, results[0].Failed())
+	_, ok, _ := checkpoint.Load(context.Background(), "r1")
+	assert.False(t, ok, "error responses must be repeated")
+	_, ok, _ = checkpoint.Load(context.Background(), "s1")
+	assert.False(t, ok, "incomplete transactions must be repeated")
+
+	results, err = NewRunner(api).WithWorkers(1).WithCheckpoint(checkpoint).WithIdempotencyStore(store).RunSlice(context.Background(), operations)
+	assert.NoError(t, err)
+	assert.False(t, results[0].Failed())
+	assert.Equal(t, "2-a", results[0].Response.Transaction.Uid)
+	_, ok, _ = checkpoint.Load(context.Background(), "r1")
+	assert.True(t, ok)
+	_, ok, _ = checkpoint.Load(context.Background(), "s1")
+	assert.True(t, ok, "failed transaction is final")
+}
+
+func TestRunner_UnknownOutcomeIsLookedUpInNextRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	refund := *vo.NewRefundRequest("1-a", 10, "return")
+	created := `{"uid":"2-a","type":"refund","status":"successful","parent_uid":"1-a","amount":10,"tracking_id":"order-1"}`
+
+	api := testdata.NewMockApi(ctrl)
+	gomock.InOrder(
+		api.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(parent("1-a", "order-1"), nil),
+		api.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(transactions(), nil),
+		api.EXPECT().Refund(gomock.Any(), refund).Return(nil, context.DeadlineExceeded),
+		api.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(nil, errors.New("timeout")),
+
+		// next run finds the refund instead of sending it again
+		api.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(transactions(created), nil),
+	)
+
+	checkpoint := NewMemoryCheckpoint()
+	store := idempotency.NewMemoryStore()
+	operations := []Operation{{Id: "r1", Kind: KindRefund, Uid: "1-a", Amount: 10, Reason: "return"}}
+
+	results, err := NewRunner(api).WithCheckpoint(checkpoint).WithIdempotencyStore(store).RunSlice(context.Background(), operations)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, context.DeadlineExceeded)
+
+	results, err = NewRunner(api).WithCheckpoint(checkpoint).WithIdempotencyStore(store).RunSlice(context.Background(), operations)
+	assert.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "2-a", results[0].Response.Transaction.Uid)
+}
+
+func TestRunner_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, uid string) (*http.Response, error) {
+		return transaction(uid, "payment", "successful"), nil
+	}).Times(3)
+
+	ch := make(chan Operation, 3)
+	ch <- Operation{Id: "1", Kind: KindStatus, Uid: "1-a"}
+	ch <- Operation{Id: "2", Kind: KindStatus, Uid: "1-b"}
+	ch <- Operation{Id: "3", Kind: KindStatus, Uid: "1-c"}
+	close(ch)
+
+	results, err := NewRunner(api).Run(context.Background(), ch)
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, "1-c", results[2].Response.Transaction.Uid)
+}
+
+func TestRunner_RateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, uid string) (*http.Response, error) {
+		return transaction(uid, "payment", "successful"), nil
+	}).Times(4)
+
+	operations := []Operation{
+		{Id: "1", Kind: KindStatus, Uid: "1-a"},
+		{Id: "2", Kind: KindStatus, Uid: "1-b"},
+		{Id: "3", Kind: KindStatus, Uid: "1-c"},
+		{Id: "4", Kind: KindStatus, Uid: "1-d"},
+	}
+
+	start := time.Now()
+	_, err := NewRunner(api).WithWorkers(4).WithRateLimit(50, time.Second).RunSlice(context.Background(), operations)
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 4*20*time.Millisecond)
+}
+
+func TestRunner_Cancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int32
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, uid string) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		cancel()
+		return transaction(uid, "payment", "successful"), nil
+	})
+
+	results, err := NewRunner(api).WithWorkers(1).RunSlice(ctx, []Operation{
+		{Id: "1", Kind: KindStatus, Uid: "1-a"},
+		{Id: "2", Kind: KindStatus, Uid: "1-b"},
+		{Id: "3", Kind: KindStatus, Uid: "1-c"},
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, "1-a", results[0].Response.Transaction.Uid)
+	// not started operations are either not dispatched or fail with ctx error
+	assert.Error(t, results[1].Err)
+	assert.Error(t, results[2].Err)
+}
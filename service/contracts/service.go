@@ -5,7 +5,15 @@ import (
 	"context"
 )
 
+//go:generate mockgen -source=service.go -destination=../../testdata/ApiServiceMock.go -package=testdata
 type ApiService interface {
+	Payment(ctx context.Context, paymentRequest vo.PaymentRequest) (vo.TransactionResponse, error)
 	Authorizations(ctx context.Context, authorizationRequest vo.AuthorizationRequest) (vo.TransactionResponse, error)
 	Capture(ctx context.Context, captureRequest vo.CaptureRequest) (vo.TransactionResponse, error)
+	Void(ctx context.Context, voidRequest vo.VoidRequest) (vo.TransactionResponse, error)
+	Refund(ctx context.Context, refundRequest vo.RefundRequest) (vo.TransactionResponse, error)
+
+	StatusByUid(ctx context.Context, uid string) (vo.TransactionResponse, error)
+	StatusByTrackingId(ctx context.Context, trackingId string) (vo.TransactionsResponse, error)
+	Balance(ctx context.Context, uid string) (vo.Balance, error)
 }
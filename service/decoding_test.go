@@ -0,0 +1,139 @@
+package service
+
+import (
+	"bepaid-sdk/testdata"
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func htmlResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+func TestApiService_NotJSONBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(htmlResponse(200, "<html>Maintenance</html>"), nil)
+
+	_, err := NewApiService(api).StatusByUid(context.Background(), "1-a")
+
+	var bodyErr *BodyError
+	assert.True(t, errors.As(err, &bodyErr))
+	assert.True(t, errors.Is(err, ErrNotJSON))
+	assert.Equal(t, 200, bodyErr.StatusCode)
+	assert.Equal(t, "<html>Maintenance</html>", bodyErr.Snippet)
+}
+
+func TestApiService_MalformedJSONBody(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(jsonResponse(`{"transaction":`+strings.Repeat(" ", 600)+`<`), nil)
+
+	_, err := NewApiService(api).StatusByUid(context.Background(), "1-a")
+
+	var bodyErr *BodyError
+	assert.True(t, errors.As(err, &bodyErr))
+	assert.Len(t, bodyErr.Snippet, snippetSize+len("..."))
+	assert.True(t, strings.HasPrefix(bodyErr.Snippet, `{"transaction":`))
+}
+
+func TestSnippet(t *testing.T) {
+	// windows-1251 page is not UTF-8, its bytes are kept except the last one looking like a cut rune
+	cp1251 := strings.Repeat("\xcf\xf0\xe8", 200)
+	assert.Equal(t, cp1251[:snippetSize-1]+"...", snippet([]byte(cp1251)))
+
+	// the rune cut by the limit is dropped
+	cyrillic := strings.Repeat("a", snippetSize-1) + "ппп"
+	assert.Equal(t, strings.Repeat("a", snippetSize-1)+"...", snippet([]byte(cyrillic)))
+
+	complete := strings.Repeat("a", snippetSize-2) + "ппп"
+	assert.Equal(t, strings.Repeat("a", snippetSize-2)+"п...", snippet([]byte(complete)))
+}
+
+func TestApiService_BodyTooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(jsonResponse(captureStatus), nil)
+
+	_, err := NewApiService(api).WithMaxBodySize(16).StatusByUid(context.Background(), "1-a")
+
+	assert.True(t, errors.Is(err, ErrBodyTooLarge))
+}
+
+func TestApiService_StatusErrorSnippet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(htmlResponse(503, "<h1>Service Unavailable</h1>"), nil)
+
+	_, err := NewApiService(api).StatusByUid(context.Background(), "1-a")
+
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, "<h1>Service Unavailable</h1>", statusErr.Snippet)
+}
+
+func TestApiService_StrictDecoding(t *testing.T) {
+	body := `{"transaction":{"uid":"1-a","status":"successful","new_field":1,"other":"x",` +
+		`"credit_card":{"last_4":"0000","wallet":"x"},"capture":{"status":"successful","network":"visa"},` +
+		`"avs_cvc_verification":{"cvc_verification":{"result_code":"1","raw":"M"}}},"meta":{},"debug":true}`
+
+	tests := []struct {
+		name   string
+		strict bool
+		fields []string
+	}{
+		{"lenient", false, nil},
+		{"strict", true, []string{
+			"debug",
+			"meta",
+			"transaction.avs_cvc_verification.cvc_verification.raw",
+			"transaction.capture.network",
+			"transaction.credit_card.wallet",
+			"transaction.new_field",
+			"transaction.other",
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			api := testdata.NewMockApi(ctrl)
+			api.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(jsonResponse(body), nil)
+
+			response, err := NewApiService(api).WithStrictDecoding(tc.strict).StatusByUid(context.Background(), "1-a")
+
+			assert.Equal(t, "1-a", response.Transaction.Uid)
+			if tc.fields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var unknownErr *UnknownFieldsError
+			assert.True(t, errors.As(err, &unknownErr))
+			assert.True(t, errors.Is(err, ErrUnknownFields))
+			assert.Equal(t, tc.fields, unknownErr.Fields)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package eventlog
+
+import (
+	"bepaid-sdk/internal/jsonfile"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileLog appends events as JSON lines to a file and syncs it after every event.
+//
+// Load reads the whole file, keep one file per day or month for large volumes
+type FileLog struct {
+	mu    sync.Mutex
+	path  string
+	lines *jsonfile.Lines
+	seq   int64
+}
+
+// OpenFileLog opens or creates the file. A partially written last line, e.g. after a crash, is removed
+func OpenFileLog(path string) (*FileLog, error) {
+	lines, complete, err := jsonfile.OpenLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileLog{path: path, lines: lines, seq: int64(len(complete))}, nil
+}
+
+// Append writes event with the next Seq. If writing fails, the file is truncated back to the previous event
+func (l *FileLog) Append(_ context.Context, event Event) (Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	event.Seq = l.seq + 1
+	if err := l.lines.Append(event); err != nil {
+		return event, err
+	}
+
+	l.seq = event.Seq
+	return event, nil
+}
+
+func (l *FileLog) Load(_ context.Context, orderId string) ([]Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var result []Event
+
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 10<<20)
+	for n := 1; s.Scan(); n++ {
+		var e Event
+		if err = json.Unmarshal(s.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("event log %s:%d: %w", l.path, n, err)
+		}
+		if e.OrderId == orderId {
+			result = append(result, e)
+		}
+	}
+
+	return result, s.Err()
+}
+
+func (l *FileLog) Close() error {
+	return l.lines.Close()
+}
@@ -0,0 +1,42 @@
+package eventlog
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Event is one request of ApiService with its result. Card and personal data are redacted
+type Event struct {
+	// Seq is assigned by Log, it grows with every appended event
+	Seq int64 `json:"seq"`
+
+	Time time.Time `json:"time"`
+
+	// OrderId is tracking_id of the order, see Recorder for how it is resolved
+	OrderId string `json:"order_id"`
+
+	// Operation is one of service.Record* names
+	Operation string `json:"operation"`
+
+	Request json.RawMessage `json:"request,omitempty"`
+
+	// Transactions of the response, one for operations and status by uid
+	Transactions []vo.Transaction `json:"transactions,omitempty"`
+
+	// GatewayError is error response of the gateway, e.g. validation errors
+	GatewayError *vo.ErrorResponse `json:"gateway_error,omitempty"`
+
+	// Error is set if the response was not received or not decoded
+	Error string `json:"error,omitempty"`
+}
+
+// Log is an append-only event log
+type Log interface {
+	// Append assigns Seq and saves the event
+	Append(ctx context.Context, event Event) (Event, error)
+
+	// Load returns events of the order ordered by Seq
+	Load(ctx context.Context, orderId string) ([]Event, error)
+}
@@ -0,0 +1,37 @@
+package eventlog
+
+import (
+	"context"
+	"sync"
+)
+
+type MemoryLog struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func NewMemoryLog() *MemoryLog {
+	return &MemoryLog{}
+}
+
+func (l *MemoryLog) Append(_ context.Context, event Event) (Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	event.Seq = int64(len(l.events)) + 1
+	l.events = append(l.events, event)
+	return event, nil
+}
+
+func (l *MemoryLog) Load(_ context.Context, orderId string) ([]Event, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var result []Event
+	for _, e := range l.events {
+		if e.OrderId == orderId {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
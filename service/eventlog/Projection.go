@@ -0,0 +1,75 @@
+package eventlog
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+	"sort"
+)
+
+// OrderState is the payment state of an order rebuilt from its events
+type OrderState struct {
+	OrderId string
+
+	// Transactions are the latest known versions of the order transactions in order of first appearance
+	Transactions []vo.Transaction
+
+	// Balance of the first successful payment or authorization, or of the last one if none is successful.
+	// Nil if the order has no payment or authorization
+	Balance *vo.Balance
+
+	// State is StateUnknown if Balance is nil
+	State vo.State
+
+	// LastSeq is Seq of the last event
+	LastSeq int64
+}
+
+// Replay loads events of the order and projects them
+func Replay(ctx context.Context, l Log, orderId string) (OrderState, error) {
+	events, err := l.Load(ctx, orderId)
+	if err != nil {
+		return OrderState{}, err
+	}
+	return Project(orderId, events), nil
+}
+
+// Project rebuilds the order state. Events are applied in order of Seq, so later status requests
+// update transactions returned by earlier operations
+func Project(orderId string, events []Event) OrderState {
+	events = append([]Event(nil), events...)
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Seq < events[j].Seq })
+
+	s := OrderState{OrderId: orderId, State: vo.StateUnknown}
+
+	index := map[string]int{}
+	for _, e := range events {
+		s.LastSeq = e.Seq
+
+		for _, t := range e.Transactions {
+			if i, ok := index[t.Uid]; ok {
+				s.Transactions[i] = t
+				continue
+			}
+			index[t.Uid] = len(s.Transactions)
+			s.Transactions = append(s.Transactions, t)
+		}
+	}
+
+	var root *vo.Transaction
+	for i, t := range s.Transactions {
+		if t.Type != "payment" && t.Type != "authorization" {
+			continue
+		}
+		if root == nil || root.Status != "successful" {
+			root = &s.Transactions[i]
+		}
+	}
+
+	if root != nil {
+		b := vo.NewBalance(*root, s.Transactions)
+		s.Balance = &b
+		s.State = b.State()
+	}
+
+	return s
+}
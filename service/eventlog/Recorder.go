@@ -0,0 +1,174 @@
+package eventlog
+
+import (
+	"bepaid-sdk/redact"
+	"bepaid-sdk/service"
+	"bepaid-sdk/service/vo"
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+// Recorder is service.Recorder appending events to Log:
+//
+//	s := service.NewApiService(api).WithRecorder(eventlog.NewRecorder(log))
+//
+// OrderId of event is tracking_id of the response transaction or of the payment or authorization request.
+// Captures, voids, refunds and status requests by uid without tracking_id get order of their parent
+// if the parent was recorded by this Recorder, otherwise the parent uid.
+// Orders of the latest transactions are remembered, see WithMaxTransactions
+type Recorder struct {
+	log             Log
+	redactor        *redact.Redactor
+	onError         func(error)
+	now             func() time.Time
+	maxTransactions int
+
+	mu     sync.Mutex
+	orders map[string]string
+	// uids of orders in the order of recording, the oldest are forgotten first
+	uids []string
+}
+
+const defaultMaxTransactions = 10000
+
+// NewRecorder redacts card and personal data with redact.Default and logs append errors with log package
+func NewRecorder(l Log) *Recorder {
+	return &Recorder{
+		log:             l,
+		redactor:        redact.Default(),
+		onError:         func(err error) { log.Printf("bepaid: event log: %v", err) },
+		now:             time.Now,
+		maxTransactions: defaultMaxTransactions,
+		orders:          map[string]string{},
+	}
+}
+
+// WithMaxTransactions limits how many transactions are remembered to find orders of their children. Default is 10000
+func (r *Recorder) WithMaxTransactions(maxTransactions int) *Recorder {
+	if maxTransactions < 1 {
+		maxTransactions = 1
+	}
+	r.maxTransactions = maxTransactions
+	return r
+}
+
+func (r *Recorder) WithRedactor(redactor *redact.Redactor) *Recorder {
+	r.redactor = redactor
+	return r
+}
+
+// WithErrorHandler is called if event can't be appended. The operation itself is not affected
+func (r *Recorder) WithErrorHandler(onError func(error)) *Recorder {
+	r.onError = onError
+	return r
+}
+
+func (r *Recorder) Record(ctx context.Context, operation string, request interface{}, response interface{}, err error) {
+	e := Event{Time: r.now(), Operation: operation}
+
+	if b, mErr := json.Marshal(request); mErr == nil {
+		e.Request = r.redactor.Redact(b)
+	}
+
+	switch resp := response.(type) {
+	case vo.TransactionResponse:
+		if resp.Transaction.Uid != "" {
+			e.Transactions = []vo.Transaction{resp.Transaction}
+		}
+		if resp.IsError() {
+			e.GatewayError = &resp.Response
+		}
+	case vo.TransactionsResponse:
+		e.Transactions = resp.Transactions
+		if resp.Response.Message != "" {
+			e.GatewayError = &resp.Response
+		}
+	}
+
+	if len(e.Transactions) > 0 {
+		e.Transactions = r.redactTransactions(e.Transactions)
+	}
+
+	if err != nil {
+		e.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	e.OrderId = r.orderId(operation, request, e.Transactions)
+	for _, t := range e.Transactions {
+		r.remember(t.Uid, e.OrderId)
+	}
+	r.mu.Unlock()
+
+	if _, err = r.log.Append(ctx, e); err != nil && r.onError != nil {
+		r.onError(err)
+	}
+}
+
+func (r *Recorder) orderId(operation string, request interface{}, transactions []vo.Transaction) string {
+	for _, t := range transactions {
+		if t.TrackingId != "" {
+			return t.TrackingId
+		}
+	}
+
+	var parent string
+	switch req := request.(type) {
+	case vo.PaymentRequest:
+		return req.Request.TrackingId
+	case vo.AuthorizationRequest:
+		return req.Request.TrackingId
+	case vo.CaptureRequest:
+		parent = req.Request.ParentUid
+	case vo.VoidRequest:
+		parent = req.Request.ParentUid
+	case vo.RefundRequest:
+		parent = req.Request.ParentUid
+	case string:
+		if operation == service.RecordStatusByTrackingId {
+			return req
+		}
+		parent = req
+	}
+
+	if order, ok := r.orders[parent]; ok {
+		return order
+	}
+	for _, t := range transactions {
+		if order, ok := r.orders[t.ParentUid]; ok {
+			return order
+		}
+	}
+	return parent
+}
+
+// remember keeps order of uid and forgets the oldest uids above maxTransactions
+func (r *Recorder) remember(uid, orderId string) {
+	if _, ok := r.orders[uid]; !ok {
+		r.uids = append(r.uids, uid)
+	}
+	r.orders[uid] = orderId
+
+	for len(r.uids) > r.maxTransactions {
+		delete(r.orders, r.uids[0])
+		r.uids[0] = ""
+		r.uids = r.uids[1:]
+	}
+}
+
+// redactTransactions returns transactions unchanged if they can't be redacted
+func (r *Recorder) redactTransactions(transactions []vo.Transaction) []vo.Transaction {
+	b, err := json.Marshal(transactions)
+	if err != nil {
+		return transactions
+	}
+
+	var redacted []vo.Transaction
+	if err = json.Unmarshal(r.redactor.Redact(b), &redacted); err != nil {
+		return transactions
+	}
+	return redacted
+}
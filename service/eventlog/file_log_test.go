@@ -0,0 +1,45 @@
+package eventlog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileLog(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	l, err := OpenFileLog(path)
+	assert.NoError(t, err)
+
+	e, err := l.Append(ctx, Event{OrderId: "order-1", Operation: "payment"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), e.Seq)
+	_, err = l.Append(ctx, Event{OrderId: "order-2", Operation: "payment"})
+	assert.NoError(t, err)
+	assert.NoError(t, l.Close())
+
+	// crash in the middle of the third event
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	assert.NoError(t, err)
+	_, err = f.WriteString(`{"seq":3,"order_`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	l, err = OpenFileLog(path)
+	assert.NoError(t, err)
+	defer l.Close()
+
+	e, err = l.Append(ctx, Event{OrderId: "order-1", Operation: "refund"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), e.Seq)
+
+	events, err := l.Load(ctx, "order-1")
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "refund", events[1].Operation)
+}
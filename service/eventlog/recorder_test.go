@@ -0,0 +1,137 @@
+package eventlog
+
+import (
+	"bepaid-sdk/service"
+	"bepaid-sdk/service/vo"
+	"bepaid-sdk/testdata"
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+const (
+	authorization = `{"transaction":{"uid":"1-a","type":"authorization","status":"successful","amount":100,"currency":"BYN","tracking_id":"order-1",
+		"credit_card":{"holder":"IVAN IVANOV","token":"tok_123","last_4":"0000"}}}`
+	capture = `{"transaction":{"uid":"2-a","parent_uid":"1-a","type":"capture","status":"successful","amount":100,"currency":"BYN"}}`
+	refund  = `{"transaction":{"uid":"3-a","parent_uid":"2-a","type":"refund","status":"successful","amount":40,"currency":"BYN"}}`
+)
+
+func TestRecorder_OrderHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().Authorization(gomock.Any(), gomock.Any()).Return(jsonResponse(authorization), nil)
+	api.EXPECT().Capture(gomock.Any(), gomock.Any()).Return(jsonResponse(capture), nil)
+	api.EXPECT().Refund(gomock.Any(), gomock.Any()).Return(nil, errors.New("timeout"))
+	api.EXPECT().Refund(gomock.Any(), gomock.Any()).Return(jsonResponse(refund), nil)
+
+	l := NewMemoryLog()
+	s := service.NewApiService(api).WithRecorder(NewRecorder(l))
+
+	card := *vo.NewCreditCard("4200000000000000", "123", "IVAN IVANOV", "01", "2030")
+	_, err := s.Authorizations(ctx, *vo.NewAuthorizationRequest(100, "BYN", "order", "order-1", true, card))
+	assert.NoError(t, err)
+	_, err = s.Capture(ctx, *vo.NewCaptureRequest(100, "1-a"))
+	assert.NoError(t, err)
+	_, err = s.Refund(ctx, *vo.NewRefundRequest("2-a", 40, "return"))
+	assert.Error(t, err)
+	_, err = s.Refund(ctx, *vo.NewRefundRequest("2-a", 40, "return"))
+	assert.NoError(t, err)
+
+	events, err := l.Load(ctx, "order-1")
+	assert.NoError(t, err)
+	assert.Len(t, events, 4)
+
+	assert.Equal(t, service.RecordAuthorization, events[0].Operation)
+	assert.NotContains(t, string(events[0].Request), "4200000000000000")
+	assert.NotContains(t, string(events[0].Request), "IVAN")
+	assert.Equal(t, "[REDACTED]", events[0].Transactions[0].CreditCard.Token)
+	assert.Equal(t, "0000", events[0].Transactions[0].CreditCard.Last4)
+	assert.Equal(t, "timeout", events[2].Error)
+
+	state := Project("order-1", events)
+	assert.Equal(t, vo.StatePartiallyRefunded, state.State)
+	assert.Equal(t, int64(40), state.Balance.Refunded)
+	assert.Len(t, state.Transactions, 3)
+	assert.Equal(t, int64(4), state.LastSeq)
+}
+
+func TestRecorder_RedactsBillingAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().Payment(gomock.Any(), gomock.Any()).Return(jsonResponse(`{"transaction":{"uid":"1-a","type":"payment",
+		"status":"successful","amount":100,"currency":"BYN","tracking_id":"order-1",
+		"billing_address":{"first_name":"Ivan","last_name":"Ivanov","address":"Nezavisimosti 1","city":"Minsk","zip":"220000","country":"BY"}}}`), nil)
+
+	l := NewMemoryLog()
+	s := service.NewApiService(api).WithRecorder(NewRecorder(l))
+
+	card := *vo.NewCreditCard("4200000000000000", "123", "IVAN IVANOV", "01", "2030")
+	address := *vo.NewBillingAddress("Ivan", "Ivanov", "Nezavisimosti 1", "BY", "Minsk", "220000")
+	_, err := s.Payment(ctx, *vo.NewPaymentRequest(100, "BYN", "order", "order-1", true, card).WithBillingAddress(address))
+	assert.NoError(t, err)
+
+	events, err := l.Load(ctx, "order-1")
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		for _, s := range []string{"Ivan", "Nezavisimosti", "Minsk", "220000"} {
+			assert.NotContains(t, string(events[0].Request), s)
+		}
+		assert.Equal(t, "[REDACTED]", events[0].Transactions[0].BillingAddress.City)
+		assert.Equal(t, "[REDACTED]", events[0].Transactions[0].BillingAddress.Zip)
+	}
+}
+
+func TestRecorder_ForgetsOldestTransactions(t *testing.T) {
+	ctx := context.Background()
+	l := NewMemoryLog()
+	r := NewRecorder(l).WithMaxTransactions(1)
+
+	r.Record(ctx, service.RecordStatusByUid, "1-a", vo.TransactionResponse{Transaction: vo.Transaction{Uid: "1-a", TrackingId: "order-1"}}, nil)
+	r.Record(ctx, service.RecordStatusByUid, "1-b", vo.TransactionResponse{Transaction: vo.Transaction{Uid: "1-b", TrackingId: "order-2"}}, nil)
+	r.Record(ctx, service.RecordCapture, *vo.NewCaptureRequest(100, "1-b"), vo.TransactionResponse{}, errors.New("timeout"))
+	r.Record(ctx, service.RecordCapture, *vo.NewCaptureRequest(100, "1-a"), vo.TransactionResponse{}, errors.New("timeout"))
+
+	events, _ := l.Load(ctx, "order-2")
+	assert.Len(t, events, 2)
+	events, _ = l.Load(ctx, "1-a")
+	assert.Len(t, events, 1, "order of forgotten parent is its uid")
+	assert.Len(t, r.orders, 1)
+}
+
+func TestProject_StatusUpdatesTransaction(t *testing.T) {
+	events := []Event{
+		{Seq: 2, Transactions: []vo.Transaction{{Uid: "1-a", Type: "payment", Status: "successful", Amount: 100}}},
+		{Seq: 1, Transactions: []vo.Transaction{{Uid: "1-a", Type: "payment", Status: "incomplete", Amount: 100}}},
+	}
+
+	state := Project("order-1", events)
+
+	assert.Equal(t, vo.StateCaptured, state.State)
+	assert.Len(t, state.Transactions, 1)
+}
+
+func TestProject_NoTransactions(t *testing.T) {
+	state := Project("order-1", []Event{{Seq: 1, Error: "timeout"}})
+
+	assert.Nil(t, state.Balance)
+	assert.Equal(t, vo.StateUnknown, state.State)
+}
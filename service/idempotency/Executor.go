@@ -0,0 +1,318 @@
+package idempotency
+
+import (
+	"bepaid-sdk/service/contracts"
+	"bepaid-sdk/service/vo"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+var (
+	ErrEmptyKey  = errors.New("idempotency: empty key")
+	ErrKeyReused = errors.New("idempotency: key is already used for another operation")
+
+	// ErrUnknownOutcome means a request without tracking_id failed ambiguously, so the transaction can't be looked up
+	// and the request isn't submitted again
+	ErrUnknownOutcome = errors.New("idempotency: outcome is unknown and there is no tracking_id to look it up")
+)
+
+// Executor makes money-moving calls idempotent.
+//
+// Before the first call to the gateway a Record is saved to Store.
+// If the call fails (timeout, network error, unexpected response), the transaction is looked up by tracking_id
+// and only if it isn't found the request is submitted again, either by Executor itself (see WithMaxAttempts)
+// or by the next call with the same key.
+//
+// Error responses of the gateway (4xx) are returned but not saved as completed, the next call with the same key
+// submits the request again
+type Executor struct {
+	service     contracts.ApiService
+	store       Store
+	maxAttempts int
+
+	locks keyLocks
+}
+
+func NewExecutor(service contracts.ApiService, store Store) *Executor {
+	return &Executor{
+		service:     service,
+		store:       store,
+		maxAttempts: 1,
+		locks:       keyLocks{locks: map[string]*keyLock{}},
+	}
+}
+
+// WithMaxAttempts sets how many times a request may be submitted during one call. Default is 1
+func (e *Executor) WithMaxAttempts(maxAttempts int) *Executor {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	e.maxAttempts = maxAttempts
+	return e
+}
+
+// Payment uses tracking_id as key if key is empty
+func (e *Executor) Payment(ctx context.Context, key string, paymentRequest vo.PaymentRequest) (vo.TransactionResponse, error) {
+	if key == "" {
+		key = paymentRequest.Request.TrackingId
+	}
+
+	return e.execute(ctx, key, operation{
+		typ:        "payment",
+		amount:     paymentRequest.Request.Amount,
+		trackingId: paymentRequest.Request.TrackingId,
+		submit: func(ctx context.Context) (vo.TransactionResponse, error) {
+			return e.service.Payment(ctx, paymentRequest)
+		},
+	})
+}
+
+// Authorization uses tracking_id as key if key is empty
+func (e *Executor) Authorization(ctx context.Context, key string, authorizationRequest vo.AuthorizationRequest) (vo.TransactionResponse, error) {
+	if key == "" {
+		key = authorizationRequest.Request.TrackingId
+	}
+
+	return e.execute(ctx, key, operation{
+		typ:        "authorization",
+		amount:     authorizationRequest.Request.Amount,
+		trackingId: authorizationRequest.Request.TrackingId,
+		submit: func(ctx context.Context) (vo.TransactionResponse, error) {
+			return e.service.Authorizations(ctx, authorizationRequest)
+		},
+	})
+}
+
+// Capture requires key, since several captures may share parent and tracking_id
+func (e *Executor) Capture(ctx context.Context, key string, captureRequest vo.CaptureRequest) (vo.TransactionResponse, error) {
+	return e.execute(ctx, key, operation{
+		typ:       "capture",
+		amount:    captureRequest.Request.Amount,
+		parentUid: captureRequest.Request.ParentUid,
+		submit: func(ctx context.Context) (vo.TransactionResponse, error) {
+			return e.service.Capture(ctx, captureRequest)
+		},
+	})
+}
+
+// Void requires key, since several voids may share parent and tracking_id
+func (e *Executor) Void(ctx context.Context, key string, voidRequest vo.VoidRequest) (vo.TransactionResponse, error) {
+	return e.execute(ctx, key, operation{
+		typ:       "void",
+		amount:    voidRequest.Request.Amount,
+		parentUid: voidRequest.Request.ParentUid,
+		submit: func(ctx context.Context) (vo.TransactionResponse, error) {
+			return e.service.Void(ctx, voidRequest)
+		},
+	})
+}
+
+// Refund requires key, since several refunds may share parent and tracking_id
+func (e *Executor) Refund(ctx context.Context, key string, refundRequest vo.RefundRequest) (vo.TransactionResponse, error) {
+	return e.execute(ctx, key, operation{
+		typ:       "refund",
+		amount:    refundRequest.Request.Amount,
+		parentUid: refundRequest.Request.ParentUid,
+		submit: func(ctx context.Context) (vo.TransactionResponse, error) {
+			return e.service.Refund(ctx, refundRequest)
+		},
+	})
+}
+
+type operation struct {
+	typ        string
+	amount     int64
+	parentUid  string
+	trackingId string
+	submit     func(ctx context.Context) (vo.TransactionResponse, error)
+}
+
+func (e *Executor) execute(ctx context.Context, key string, op operation) (vo.TransactionResponse, error) {
+	if key == "" {
+		return vo.TransactionResponse{}, ErrEmptyKey
+	}
+
+	unlock := e.locks.lock(key)
+	defer unlock()
+
+	record, ok, err := e.store.Get(ctx, key)
+	if err != nil {
+		return vo.TransactionResponse{}, err
+	}
+
+	if ok {
+		if record.Type != op.typ || record.Amount != op.amount || record.ParentUid != op.parentUid {
+			return vo.TransactionResponse{}, fmt.Errorf("%w: %s", ErrKeyReused, key)
+		}
+		if record.Completed() {
+			return *record.Response, nil
+		}
+
+		if record.Rejected {
+			// previous call was rejected by the gateway, nothing to look up
+			record.Rejected = false
+			if err = e.store.Put(ctx, record); err != nil {
+				return vo.TransactionResponse{}, err
+			}
+		} else if tr, found, err := e.lookup(ctx, record); err != nil {
+			// previous call failed with unknown outcome
+			return vo.TransactionResponse{}, err
+		} else if found {
+			return e.complete(ctx, record, tr)
+		}
+	} else {
+		if record, err = e.newRecord(ctx, key, op); err != nil {
+			return vo.TransactionResponse{}, err
+		}
+		if err = e.store.Put(ctx, record); err != nil {
+			return vo.TransactionResponse{}, err
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		tr, err := op.submit(ctx)
+		if err == nil && tr.IsError() {
+			record.Rejected = true
+			if err = e.store.Put(ctx, record); err != nil {
+				return tr, err
+			}
+			return tr, nil
+		}
+		if err == nil {
+			return e.complete(ctx, record, tr)
+		}
+
+		foundTr, found, lookupErr := e.lookup(ctx, record)
+		if lookupErr == nil && found {
+			return e.complete(ctx, record, foundTr)
+		}
+
+		if lookupErr != nil || attempt >= e.maxAttempts {
+			return vo.TransactionResponse{}, err
+		}
+	}
+}
+
+func (e *Executor) newRecord(ctx context.Context, key string, op operation) (Record, error) {
+	trackingId := op.trackingId
+	if op.parentUid != "" {
+		parent, err := e.service.StatusByUid(ctx, op.parentUid)
+		if err != nil {
+			return Record{}, err
+		}
+		if parent.IsError() {
+			return Record{}, fmt.Errorf("idempotency: status by uid %s: %s", op.parentUid, parent.Response.Message)
+		}
+		trackingId = parent.Transaction.TrackingId
+	}
+
+	record := Record{
+		Key:        key,
+		TrackingId: trackingId,
+		Type:       op.typ,
+		Amount:     op.amount,
+		ParentUid:  op.parentUid,
+	}
+	if trackingId == "" {
+		return record, nil
+	}
+
+	transactions, err := e.service.StatusByTrackingId(ctx, trackingId)
+	if err == nil {
+		err = gatewayError(trackingId, transactions)
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	for _, t := range transactions.Transactions {
+		record.KnownUids = append(record.KnownUids, t.Uid)
+	}
+
+	return record, nil
+}
+
+// lookup searches for the transaction created by record's operation among transactions with record.TrackingId
+func (e *Executor) lookup(ctx context.Context, record Record) (vo.TransactionResponse, bool, error) {
+	if record.TrackingId == "" {
+		return vo.TransactionResponse{}, false, fmt.Errorf("%w: %s", ErrUnknownOutcome, record.Key)
+	}
+
+	transactions, err := e.service.StatusByTrackingId(ctx, record.TrackingId)
+	if err == nil {
+		err = gatewayError(record.TrackingId, transactions)
+	}
+	if err != nil {
+		return vo.TransactionResponse{}, false, err
+	}
+
+	known := make(map[string]struct{}, len(record.KnownUids))
+	for _, uid := range record.KnownUids {
+		known[uid] = struct{}{}
+	}
+
+	for _, t := range transactions.Transactions {
+		if _, ok := known[t.Uid]; ok {
+			continue
+		}
+		if t.Type == record.Type && t.Amount == record.Amount && t.ParentUid == record.ParentUid {
+			return vo.TransactionResponse{Transaction: t}, true, nil
+		}
+	}
+
+	return vo.TransactionResponse{}, false, nil
+}
+
+// gatewayError returns error response of status request by tracking_id except 404 of unknown tracking_id.
+// Without the list of transactions an earlier transaction could be taken for the result of a new key
+func gatewayError(trackingId string, transactions vo.TransactionsResponse) error {
+	if transactions.Response.Message == "" || transactions.Response.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return fmt.Errorf("idempotency: status by tracking_id %s: %s", trackingId, transactions.Response.Message)
+}
+
+func (e *Executor) complete(ctx context.Context, record Record, tr vo.TransactionResponse) (vo.TransactionResponse, error) {
+	record.Response = &tr
+	if err := e.store.Put(ctx, record); err != nil {
+		return tr, err
+	}
+	return tr, nil
+}
+
+// keyLocks serializes calls with the same key inside one process
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+func (l *keyLocks) lock(key string) (unlock func()) {
+	l.mu.Lock()
+	kl, ok := l.locks[key]
+	if !ok {
+		kl = &keyLock{}
+		l.locks[key] = kl
+	}
+	kl.refs++
+	l.mu.Unlock()
+
+	kl.Lock()
+
+	return func() {
+		kl.Unlock()
+
+		l.mu.Lock()
+		kl.refs--
+		if kl.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
@@ -0,0 +1,34 @@
+package idempotency
+
+import (
+	"bepaid-sdk/internal/jsonfile"
+	"context"
+)
+
+// FileStore keeps every record as a JSON file in dir
+type FileStore struct {
+	dir *jsonfile.Dir
+}
+
+// NewFileStore creates dir if it doesn't exist. Files are named with "idempotency-" prefix, so dir can be shared with outbox.FileStore
+func NewFileStore(dir string) (*FileStore, error) {
+	d, err := jsonfile.OpenDir(dir, "idempotency")
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: d}, nil
+}
+
+func (s *FileStore) Get(_ context.Context, key string) (Record, bool, error) {
+	var r Record
+	ok, err := s.dir.Read(key, &r)
+	if !ok || err != nil {
+		return Record{}, false, err
+	}
+	return r, true, nil
+}
+
+// Put writes record to temporary file and renames it, so a crash never leaves partially written record
+func (s *FileStore) Put(_ context.Context, record Record) error {
+	return s.dir.Write(record.Key, record)
+}
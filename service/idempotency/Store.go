@@ -0,0 +1,44 @@
+package idempotency
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+)
+
+// Record is the state of an idempotent operation saved before the first call to the gateway
+type Record struct {
+	Key string `json:"key"`
+
+	//tracking_id, по которому ищется транзакция после неоднозначной ошибки
+	TrackingId string `json:"tracking_id"`
+
+	//тип транзакции: payment, authorization, capture, void, refund
+	Type      string `json:"type"`
+	Amount    int64  `json:"amount"`
+	ParentUid string `json:"parent_uid,omitempty"`
+
+	//UID транзакций с тем же tracking_id, существовавших до первой отправки запроса
+	KnownUids []string `json:"known_uids,omitempty"`
+
+	//ответ шлюза. nil, пока результат операции неизвестен
+	Response *vo.TransactionResponse `json:"response,omitempty"`
+
+	//true, если шлюз отклонил последний запрос ответом с ошибкой и транзакция не создана
+	Rejected bool `json:"rejected,omitempty"`
+}
+
+// Completed reports whether outcome of the operation is known
+func (r Record) Completed() bool {
+	return r.Response != nil
+}
+
+// Store persists records between calls and process restarts
+//
+// Implementations must be safe for concurrent use
+type Store interface {
+	// Get returns record by key. ok is false if there is no such record
+	Get(ctx context.Context, key string) (record Record, ok bool, err error)
+
+	// Put creates or replaces record with record.Key
+	Put(ctx context.Context, record Record) error
+}
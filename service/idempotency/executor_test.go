@@ -0,0 +1,201 @@
+package idempotency
+
+import (
+	"bepaid-sdk/service/vo"
+	"bepaid-sdk/testdata"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func transaction(uid, typ, parentUid string, amount int64) vo.Transaction {
+	return vo.Transaction{Uid: uid, Type: typ, ParentUid: parentUid, Amount: amount, Status: "successful", TrackingId: "order-1"}
+}
+
+func transactions(t ...vo.Transaction) vo.TransactionsResponse {
+	return vo.TransactionsResponse{Transactions: t}
+}
+
+func paymentRequest() vo.PaymentRequest {
+	return *vo.NewPaymentRequest(100, "BYN", "order", "order-1", true, vo.CreditCard{})
+}
+
+func TestExecutor_PaymentCompletedIsNotResubmitted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := testdata.NewMockApiService(ctrl)
+	service.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(transactions(), nil)
+	service.EXPECT().Payment(gomock.Any(), paymentRequest()).
+		Return(vo.TransactionResponse{Transaction: transaction("1-a", "payment", "", 100)}, nil)
+
+	e := NewExecutor(service, NewMemoryStore())
+
+	for i := 0; i < 2; i++ {
+		tr, err := e.Payment(context.Background(), "", paymentRequest())
+
+		assert.Nil(t, err)
+		assert.Equal(t, "1-a", tr.Transaction.Uid)
+	}
+}
+
+func TestExecutor_TrackingIdErrorResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := testdata.NewMockApiService(ctrl)
+	gomock.InOrder(
+		// no list of known transactions, the payment is not sent
+		service.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").
+			Return(vo.TransactionsResponse{Response: vo.ErrorResponse{Message: "Internal error", StatusCode: 422}}, nil),
+		// unknown tracking_id
+		service.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").
+			Return(vo.TransactionsResponse{Response: vo.ErrorResponse{Message: "Not found", StatusCode: 404}}, nil),
+		service.EXPECT().Payment(gomock.Any(), paymentRequest()).
+			Return(vo.TransactionResponse{Transaction: transaction("1-a", "payment", "", 100)}, nil),
+	)
+	e := NewExecutor(service, NewMemoryStore())
+
+	_, err := e.Payment(context.Background(), "", paymentRequest())
+	assert.EqualError(t, err, "idempotency: status by tracking_id order-1: Internal error")
+
+	tr, err := e.Payment(context.Background(), "", paymentRequest())
+	assert.Nil(t, err)
+	assert.Equal(t, "1-a", tr.Transaction.Uid)
+}
+
+func TestExecutor_PaymentAmbiguousFailureFoundByTrackingId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := testdata.NewMockApiService(ctrl)
+	gomock.InOrder(
+		// previous declined payment with the same tracking_id
+		service.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(transactions(transaction("0-a", "payment", "", 100)), nil),
+		service.EXPECT().Payment(gomock.Any(), paymentRequest()).Return(vo.TransactionResponse{}, context.DeadlineExceeded),
+		service.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").
+			Return(transactions(transaction("0-a", "payment", "", 100), transaction("1-a", "payment", "", 100)), nil),
+	)
+
+	tr, err := NewExecutor(service, NewMemoryStore()).WithMaxAttempts(3).Payment(context.Background(), "", paymentRequest())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "1-a", tr.Transaction.Uid)
+}
+
+func TestExecutor_PaymentAmbiguousFailureResubmitted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := testdata.NewMockApiService(ctrl)
+	gomock.InOrder(
+		service.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(transactions(), nil),
+		service.EXPECT().Payment(gomock.Any(), paymentRequest()).Return(vo.TransactionResponse{}, errors.New("connection reset")),
+		service.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(transactions(), nil),
+		service.EXPECT().Payment(gomock.Any(), paymentRequest()).
+			Return(vo.TransactionResponse{Transaction: transaction("1-a", "payment", "", 100)}, nil),
+	)
+
+	tr, err := NewExecutor(service, NewMemoryStore()).WithMaxAttempts(2).Payment(context.Background(), "", paymentRequest())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "1-a", tr.Transaction.Uid)
+}
+
+func TestExecutor_PendingRecordResolvedOnNextCall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	refund := *vo.NewRefundRequest("1-a", 30, "reason")
+	service := testdata.NewMockApiService(ctrl)
+	gomock.InOrder(
+		service.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(vo.TransactionResponse{Transaction: transaction("1-a", "payment", "", 100)}, nil),
+		// refund of the same amount made earlier with another key
+		service.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").
+			Return(transactions(transaction("1-a", "payment", "", 100), transaction("2-a", "refund", "1-a", 30)), nil),
+		service.EXPECT().Refund(gomock.Any(), refund).Return(vo.TransactionResponse{}, errors.New("timeout")),
+		service.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").
+			Return(transactions(transaction("1-a", "payment", "", 100), transaction("2-a", "refund", "1-a", 30)), nil),
+
+		// next call
+		service.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").
+			Return(transactions(transaction("1-a", "payment", "", 100), transaction("2-a", "refund", "1-a", 30), transaction("3-a", "refund", "1-a", 30)), nil),
+	)
+
+	e := NewExecutor(service, NewMemoryStore())
+
+	_, err := e.Refund(context.Background(), "refund-2", refund)
+	assert.NotNil(t, err)
+
+	tr, err := e.Refund(context.Background(), "refund-2", refund)
+	assert.Nil(t, err)
+	assert.Equal(t, "3-a", tr.Transaction.Uid)
+}
+
+func TestExecutor_KeyErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	service := testdata.NewMockApiService(ctrl)
+	store := NewMemoryStore()
+	_ = store.Put(context.Background(), Record{Key: "k", Type: "refund", Amount: 30, ParentUid: "1-a"})
+
+	e := NewExecutor(service, store)
+
+	_, err := e.Refund(context.Background(), "", *vo.NewRefundRequest("1-a", 30, "reason"))
+	assert.True(t, errors.Is(err, ErrEmptyKey))
+
+	_, err = e.Refund(context.Background(), "k", *vo.NewRefundRequest("1-a", 40, "reason"))
+	assert.True(t, errors.Is(err, ErrKeyReused))
+}
+
+func TestExecutor_WithoutTrackingIdNoLookup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payment := *vo.NewPaymentRequest(100, "BYN", "order", "", true, vo.CreditCard{})
+	service := testdata.NewMockApiService(ctrl)
+	service.EXPECT().Payment(gomock.Any(), payment).Return(vo.TransactionResponse{}, errors.New("connection reset"))
+
+	e := NewExecutor(service, NewMemoryStore()).WithMaxAttempts(3)
+
+	_, err := e.Payment(context.Background(), "payment-1", payment)
+	assert.EqualError(t, err, "connection reset")
+
+	_, err = e.Payment(context.Background(), "payment-1", payment)
+	assert.ErrorIs(t, err, ErrUnknownOutcome)
+}
+
+func TestExecutor_GatewayErrorIsNotCompleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rejected := vo.TransactionResponse{}
+	rejected.Response.Message = "Amount must be greater than 0"
+
+	service := testdata.NewMockApiService(ctrl)
+	gomock.InOrder(
+		service.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(transactions(), nil),
+		service.EXPECT().Payment(gomock.Any(), paymentRequest()).Return(rejected, nil),
+		service.EXPECT().Payment(gomock.Any(), paymentRequest()).
+			Return(vo.TransactionResponse{Transaction: transaction("1-a", "payment", "", 100)}, nil),
+	)
+
+	store := NewMemoryStore()
+	e := NewExecutor(service, store)
+
+	tr, err := e.Payment(context.Background(), "", paymentRequest())
+	assert.Nil(t, err)
+	assert.True(t, tr.IsError())
+
+	record, _, _ := store.Get(context.Background(), "order-1")
+	assert.False(t, record.Completed())
+	assert.True(t, record.Rejected)
+
+	tr, err = e.Payment(context.Background(), "", paymentRequest())
+	assert.Nil(t, err)
+	assert.Equal(t, "1-a", tr.Transaction.Uid)
+}
@@ -0,0 +1,41 @@
+package idempotency
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	s, err := NewFileStore(filepath.Join(t.TempDir(), "records"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	key := strings.Repeat("long/key:", 50)
+
+	_, ok, err := s.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	r := Record{Key: key, TrackingId: "order-1", Type: "payment", Amount: 100, KnownUids: []string{"0-a"}}
+	assert.Nil(t, s.Put(ctx, r))
+
+	ar, ok, err := s.Get(ctx, key)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, r, ar)
+	assert.False(t, ar.Completed())
+
+	r.Response = &vo.TransactionResponse{Transaction: vo.Transaction{Uid: "1-a"}}
+	assert.Nil(t, s.Put(ctx, r))
+
+	ar, _, _ = s.Get(ctx, key)
+	assert.True(t, ar.Completed())
+	assert.Equal(t, "1-a", ar.Response.Transaction.Uid)
+}
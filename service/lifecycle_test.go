@@ -0,0 +1,111 @@
+package service
+
+import (
+	"bepaid-sdk/service/vo"
+	"bepaid-sdk/testdata"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const failedAuthorizationStatus = `{"transaction":{"uid":"1-310b0da80b","status":"failed","amount":100,"currency":"USD","type":"authorization","tracking_id":"order-1"}}`
+
+func TestApiService_LifecycleGuardCaptureFailedAuthorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	expectOrderStatus(api, "1-310b0da80b", failedAuthorizationStatus)
+
+	_, err := NewApiService(api).WithLifecycleGuard(true).Capture(context.Background(), *vo.NewCaptureRequest(50, "1-310b0da80b"))
+
+	assert.True(t, errors.Is(err, ErrOperationNotAllowed))
+	var e *OperationNotAllowedError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, vo.OperationCapture, e.Operation)
+		assert.Equal(t, vo.StateFailed, e.State)
+	}
+}
+
+func TestApiService_LifecycleGuardVoidCaptured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(authorizationStatus), nil)
+	api.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(jsonResponse(`{"transactions":[
+		{"uid":"2-310b0da80b","status":"successful","amount":100,"type":"capture","parent_uid":"1-310b0da80b"}
+	]}`), nil)
+
+	_, err := NewApiService(api).WithLifecycleGuard(true).Void(context.Background(), *vo.NewVoidRequest("1-310b0da80b", 100))
+
+	var e *OperationNotAllowedError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, vo.StateCaptured, e.State)
+	}
+}
+
+func TestApiService_LifecycleGuardCaptureOfCapture(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	for i := 0; i < 2; i++ {
+		api.EXPECT().StatusByUid(gomock.Any(), "2-310b0da80b").Return(jsonResponse(captureStatus), nil)
+	}
+	s := NewApiService(api).WithLifecycleGuard(true)
+
+	_, err := s.Capture(context.Background(), *vo.NewCaptureRequest(10, "2-310b0da80b"))
+	var e *OperationNotAllowedError
+	if assert.True(t, errors.As(err, &e)) {
+		assert.Equal(t, vo.OperationCapture, e.Operation)
+		assert.Equal(t, "capture", e.ParentType)
+	}
+
+	_, err = s.Void(context.Background(), *vo.NewVoidRequest("2-310b0da80b", 10))
+	assert.True(t, errors.Is(err, ErrOperationNotAllowed))
+}
+
+func TestApiService_LifecycleGuardWithoutTrackingId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(
+		`{"transaction":{"uid":"1-310b0da80b","status":"successful","amount":100,"currency":"USD","type":"authorization"}}`), nil)
+
+	_, err := NewApiService(api).WithLifecycleGuard(true).Capture(context.Background(), *vo.NewCaptureRequest(10, "1-310b0da80b"))
+
+	assert.True(t, errors.Is(err, ErrNoTrackingId))
+}
+
+func TestApiService_LifecycleGuardRefundAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "2-310b0da80b").Return(jsonResponse(captureStatus), nil)
+	expectOrderStatus(api, "1-310b0da80b", authorizationStatus)
+	api.EXPECT().Refund(gomock.Any(), *vo.NewRefundRequest("2-310b0da80b", 10, "reason")).Return(jsonResponse(`{"transaction":{"status":"successful","type":"refund"}}`), nil)
+
+	tr, err := NewApiService(api).WithLifecycleGuard(true).Refund(context.Background(), *vo.NewRefundRequest("2-310b0da80b", 10, "reason"))
+
+	assert.Nil(t, err)
+	assert.True(t, tr.IsRefund())
+}
+
+func TestApiService_LifecycleGuardDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().Void(gomock.Any(), *vo.NewVoidRequest("1-310b0da80b", 100)).Return(jsonResponse(`{"response":{"message":"Transaction is already captured"}}`), nil)
+
+	tr, err := NewApiService(api).Void(context.Background(), *vo.NewVoidRequest("1-310b0da80b", 100))
+
+	assert.Nil(t, err)
+	assert.True(t, tr.IsError())
+}
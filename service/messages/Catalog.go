@@ -0,0 +1,103 @@
+package messages
+
+import (
+	"bepaid-sdk/service/vo"
+	"strings"
+	"sync"
+)
+
+// Catalog maps gateway codes to messages for customers. Gateway messages are written for merchants
+// and come in the language chosen by the gateway, checkout pages should show catalog messages instead:
+//
+//	c := messages.Default()
+//	text := c.TransactionMessage(tr.Transaction, vo.LanguageBelarusian)
+//
+// Keys are "bank.NN" for response codes of the issuer (ProcessingResult.BankCode), "status.<status>"
+// for transactions without known issuer code and KeyError for gateway error responses
+type Catalog struct {
+	fallback vo.Language
+
+	mu       sync.RWMutex
+	messages map[string]map[vo.Language]string
+}
+
+// New returns empty catalog, messages without translation to the requested language are taken in fallback language
+func New(fallback vo.Language) *Catalog {
+	return &Catalog{fallback: fallback, messages: map[string]map[vo.Language]string{}}
+}
+
+// Default returns catalog of issuer response codes and transaction statuses in Russian, Belarusian and English.
+// English is the fallback language
+func Default() *Catalog {
+	c := New(vo.LanguageEnglish)
+	for key, translations := range defaultMessages {
+		c.With(key, translations)
+	}
+	return c
+}
+
+// With adds or replaces translations of key
+func (c *Catalog) With(key string, translations map[vo.Language]string) *Catalog {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := c.messages[key]
+	if !ok {
+		m = make(map[vo.Language]string, len(translations))
+		c.messages[key] = m
+	}
+	for lang, text := range translations {
+		m[lang] = text
+	}
+	return c
+}
+
+// Lookup returns the message of key in lang or in the fallback language
+func (c *Catalog) Lookup(key string, lang vo.Language) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	m, ok := c.messages[key]
+	if !ok {
+		return "", false
+	}
+	if text, ok := m[normalize(lang)]; ok {
+		return text, true
+	}
+	text, ok := m[c.fallback]
+	return text, ok
+}
+
+// TransactionMessage returns message of issuer response code of failed transaction or of the transaction status,
+// whichever is found first. Gateway message is returned if nothing is found
+func (c *Catalog) TransactionMessage(t vo.Transaction, lang vo.Language) string {
+	if t.Status == "failed" {
+		if p := t.ProcessingResult(); p != nil && p.BankCode != "" {
+			if text, ok := c.Lookup("bank."+p.BankCode, lang); ok {
+				return text
+			}
+		}
+	}
+
+	if text, ok := c.Lookup("status."+t.Status, lang); ok {
+		return text
+	}
+	return t.Message
+}
+
+// ErrorMessage returns the generic error message, messages of gateway errors are written for merchants
+func (c *Catalog) ErrorMessage(r vo.ErrorResponse, lang vo.Language) string {
+	if text, ok := c.Lookup(KeyError, lang); ok {
+		return text
+	}
+	return r.Message
+}
+
+// normalize turns language tags like "ru-RU" into "ru"
+func normalize(lang vo.Language) vo.Language {
+	s := strings.ToLower(string(lang))
+	if i := strings.IndexAny(s, "-_"); i > 0 {
+		s = s[:i]
+	}
+	return vo.Language(s)
+}
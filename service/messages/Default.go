@@ -0,0 +1,133 @@
+package messages
+
+import "bepaid-sdk/service/vo"
+
+// KeyError is the message of gateway errors without known code
+const KeyError = "error"
+
+const (
+	ru = vo.LanguageRussian
+	be = vo.LanguageBelarusian
+	en = vo.LanguageEnglish
+)
+
+// defaultMessages are ISO 8583 response codes of issuers and transaction statuses
+var defaultMessages = map[string]map[vo.Language]string{
+	KeyError: {
+		ru: "Не удалось выполнить оплату. Попробуйте позже",
+		be: "Не ўдалося выканаць аплату. Паспрабуйце пазней",
+		en: "Payment could not be processed. Please try again later",
+	},
+
+	"status.successful": {
+		ru: "Оплата прошла успешно",
+		be: "Аплата прайшла паспяхова",
+		en: "Payment successful",
+	},
+	"status.failed": {
+		ru: "Оплата отклонена",
+		be: "Аплата адхілена",
+		en: "Payment declined",
+	},
+	"status.incomplete": {
+		ru: "Оплата не завершена, подтвердите её в банке",
+		be: "Аплата не завершана, пацвердзіце яе ў банку",
+		en: "Payment is not completed, please confirm it with your bank",
+	},
+	"status.expired": {
+		ru: "Время на оплату истекло",
+		be: "Час на аплату скончыўся",
+		en: "Payment time has expired",
+	},
+
+	"bank.03": {
+		ru: "Магазин не может принять оплату этой картой",
+		be: "Крама не можа прыняць аплату гэтай карткай",
+		en: "The shop can't accept this card",
+	},
+	"bank.04": {
+		ru: "Карта заблокирована. Обратитесь в банк",
+		be: "Картка заблакавана. Звярніцеся ў банк",
+		en: "The card is blocked. Please contact your bank",
+	},
+	"bank.05": {
+		ru: "Банк отклонил оплату. Обратитесь в банк или используйте другую карту",
+		be: "Банк адхіліў аплату. Звярніцеся ў банк або выкарыстайце іншую картку",
+		en: "Your bank declined the payment. Please contact your bank or use another card",
+	},
+	"bank.12": {
+		ru: "Операция недоступна для этой карты",
+		be: "Аперацыя недаступная для гэтай карткі",
+		en: "The transaction is not allowed for this card",
+	},
+	"bank.13": {
+		ru: "Неверная сумма",
+		be: "Няправільная сума",
+		en: "Invalid amount",
+	},
+	"bank.14": {
+		ru: "Неверный номер карты",
+		be: "Няправільны нумар карткі",
+		en: "Invalid card number",
+	},
+	"bank.41": {
+		ru: "Карта заблокирована. Обратитесь в банк",
+		be: "Картка заблакавана. Звярніцеся ў банк",
+		en: "The card is blocked. Please contact your bank",
+	},
+	"bank.43": {
+		ru: "Карта заблокирована. Обратитесь в банк",
+		be: "Картка заблакавана. Звярніцеся ў банк",
+		en: "The card is blocked. Please contact your bank",
+	},
+	"bank.51": {
+		ru: "Недостаточно средств на карте",
+		be: "Недастаткова сродкаў на картцы",
+		en: "Insufficient funds",
+	},
+	"bank.54": {
+		ru: "Срок действия карты истёк",
+		be: "Тэрмін дзеяння карткі скончыўся",
+		en: "The card has expired",
+	},
+	"bank.55": {
+		ru: "Неверный PIN-код",
+		be: "Няправільны PIN-код",
+		en: "Incorrect PIN",
+	},
+	"bank.57": {
+		ru: "Операция запрещена для этой карты. Обратитесь в банк",
+		be: "Аперацыя забаронена для гэтай карткі. Звярніцеся ў банк",
+		en: "The transaction is not permitted for this card. Please contact your bank",
+	},
+	"bank.61": {
+		ru: "Превышен лимит по сумме операций",
+		be: "Перавышаны ліміт па суме аперацый",
+		en: "The card amount limit is exceeded",
+	},
+	"bank.62": {
+		ru: "Карта ограничена. Обратитесь в банк",
+		be: "Картка абмежаваная. Звярніцеся ў банк",
+		en: "The card is restricted. Please contact your bank",
+	},
+	"bank.65": {
+		ru: "Превышен лимит количества операций",
+		be: "Перавышаны ліміт колькасці аперацый",
+		en: "The card transaction count limit is exceeded",
+	},
+	"bank.75": {
+		ru: "Превышено число попыток ввода PIN-кода",
+		be: "Перавышана колькасць спроб уводу PIN-кода",
+		en: "Too many incorrect PIN attempts",
+	},
+	"bank.91": {
+		ru: "Банк временно недоступен. Попробуйте позже",
+		be: "Банк часова недаступны. Паспрабуйце пазней",
+		en: "Your bank is temporarily unavailable. Please try again later",
+	},
+	"bank.96": {
+		ru: "Ошибка в банке. Попробуйте позже",
+		be: "Памылка ў банку. Паспрабуйце пазней",
+		en: "Bank system error. Please try again later",
+	},
+}
@@ -0,0 +1,66 @@
+package messages
+
+import (
+	"bepaid-sdk/service/vo"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func declined(bankCode string) vo.Transaction {
+	return vo.Transaction{
+		Type:    "payment",
+		Status:  "failed",
+		Message: "Insufficient funds. Contact issuer",
+		Payment: &vo.ProcessingResult{BankCode: bankCode},
+	}
+}
+
+func TestCatalog_TransactionMessage(t *testing.T) {
+	c := Default()
+
+	assert.Equal(t, "Недастаткова сродкаў на картцы", c.TransactionMessage(declined("51"), vo.LanguageBelarusian))
+	assert.Equal(t, "Недостаточно средств на карте", c.TransactionMessage(declined("51"), "ru-RU"))
+	assert.Equal(t, "Insufficient funds", c.TransactionMessage(declined("51"), "de"))
+
+	// unknown bank code falls back to status
+	assert.Equal(t, "Оплата отклонена", c.TransactionMessage(declined("99"), vo.LanguageRussian))
+
+	// bank code of successful transaction is ignored
+	successful := declined("05")
+	successful.Status = "successful"
+	assert.Equal(t, "Payment successful", c.TransactionMessage(successful, vo.LanguageEnglish))
+
+	unknown := vo.Transaction{Status: "pending", Message: "Pending"}
+	assert.Equal(t, "Pending", c.TransactionMessage(unknown, vo.LanguageEnglish))
+}
+
+func TestCatalog_With(t *testing.T) {
+	c := Default().With("bank.05", map[vo.Language]string{
+		vo.LanguageRussian: "Банк отклонил оплату",
+		vo.LanguageEnglish: "Declined by the bank",
+	})
+
+	var tr vo.TransactionResponse
+	assert.NoError(t, json.Unmarshal([]byte(`{"transaction":{"type":"payment","status":"failed",
+		"payment":{"bank_code":"05"}}}`), &tr))
+
+	assert.Equal(t, "Банк отклонил оплату", c.TransactionMessage(tr.Transaction, vo.LanguageRussian))
+	assert.Equal(t, "Declined by the bank", c.TransactionMessage(tr.Transaction, "de"))
+}
+
+func TestCatalog_ErrorMessage(t *testing.T) {
+	assert.Equal(t, "Не ўдалося выканаць аплату. Паспрабуйце пазней",
+		Default().ErrorMessage(vo.ErrorResponse{Message: "Amount must be greater than 0"}, vo.LanguageBelarusian))
+	assert.Equal(t, "Amount must be greater than 0",
+		New(vo.LanguageEnglish).ErrorMessage(vo.ErrorResponse{Message: "Amount must be greater than 0"}, vo.LanguageRussian))
+}
+
+func TestDefault_HasAllLanguages(t *testing.T) {
+	for key, translations := range defaultMessages {
+		for _, lang := range []vo.Language{ru, be, en} {
+			assert.NotEmpty(t, translations[lang], "%s %s", key, lang)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package outbox
+
+import (
+	"bepaid-sdk/internal/jsonfile"
+	"context"
+)
+
+// FileStore keeps every message as a JSON file in dir
+type FileStore struct {
+	dir *jsonfile.Dir
+}
+
+// NewFileStore creates dir if it doesn't exist. Files are named with "outbox-" prefix, so dir can be shared with idempotency.FileStore
+func NewFileStore(dir string) (*FileStore, error) {
+	d, err := jsonfile.OpenDir(dir, "outbox")
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: d}, nil
+}
+
+func (s *FileStore) Get(_ context.Context, id string) (Message, bool, error) {
+	var m Message
+	ok, err := s.dir.Read(id, &m)
+	if !ok || err != nil {
+		return Message{}, false, err
+	}
+	return m, true, nil
+}
+
+// Put writes message to temporary file and renames it, so a crash never leaves partially written message
+func (s *FileStore) Put(_ context.Context, message Message) error {
+	return s.dir.Write(message.Id, message)
+}
+
+func (s *FileStore) Unfinished(_ context.Context) ([]Message, error) {
+	var messages []Message
+	err := s.dir.Each(func(unmarshal func(v interface{}) error) error {
+		var m Message
+		if err := unmarshal(&m); err != nil {
+			return err
+		}
+		if !m.finished() {
+			messages = append(messages, m)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortByCreatedAt(messages)
+	return messages, nil
+}
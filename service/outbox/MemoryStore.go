@@ -0,0 +1,56 @@
+package outbox
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MemoryStore keeps messages in memory. Messages are lost on restart, use it in tests
+type MemoryStore struct {
+	mu       sync.Mutex
+	messages map[string]Message
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{messages: map[string]Message{}}
+}
+
+func (s *MemoryStore) Get(_ context.Context, id string) (Message, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.messages[id]
+	return m, ok, nil
+}
+
+func (s *MemoryStore) Put(_ context.Context, message Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages[message.Id] = message
+	return nil
+}
+
+func (s *MemoryStore) Unfinished(_ context.Context) ([]Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var messages []Message
+	for _, m := range s.messages {
+		if !m.finished() {
+			messages = append(messages, m)
+		}
+	}
+	sortByCreatedAt(messages)
+	return messages, nil
+}
+
+func sortByCreatedAt(messages []Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		if messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].Id < messages[j].Id
+		}
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+}
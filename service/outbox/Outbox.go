@@ -0,0 +1,181 @@
+package outbox
+
+import (
+	"bepaid-sdk/api/contracts"
+	"bepaid-sdk/service"
+	"bepaid-sdk/service/idempotency"
+	"bepaid-sdk/service/vo"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultMaxAttempts = 5
+
+var (
+	ErrEmptyId     = errors.New("outbox: empty message id")
+	ErrUnknownKind = errors.New("outbox: unknown message kind")
+	ErrIdReused    = errors.New("outbox: id is already used for another operation")
+)
+
+// Outbox persists captures, voids and refunds before they are sent, so an operation accepted by the
+// local system is sent to the gateway even if the process crashes or the gateway is unavailable:
+//
+//	o := outbox.New(api, messages, records)
+//	err := o.Enqueue(ctx, outbox.Message{Id: refundId, Kind: outbox.KindRefund, ParentUid: uid, Amount: 100, Reason: "return"})
+//	go o.Run(ctx, time.Second)
+//
+// Messages are sent with idempotency.Executor, operation with unknown outcome (timeout, crash during the call)
+// is looked up by tracking_id of the parent transaction before it is sent again.
+// records are executor records, they must be persistent as well, e.g. idempotency.FileStore.
+//
+// Run only one worker per Store
+type Outbox struct {
+	store       Store
+	executor    *idempotency.Executor
+	maxAttempts int
+	now         func() time.Time
+
+	mu sync.Mutex
+}
+
+func New(api contracts.Api, store Store, records idempotency.Store) *Outbox {
+	return &Outbox{
+		store:       store,
+		executor:    idempotency.NewExecutor(service.NewApiService(api), records).WithMaxAttempts(1),
+		maxAttempts: defaultMaxAttempts,
+		now:         time.Now,
+	}
+}
+
+// WithMaxAttempts sets the number of dispatches before the message is failed. Default is 5
+func (o *Outbox) WithMaxAttempts(maxAttempts int) *Outbox {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	o.maxAttempts = maxAttempts
+	return o
+}
+
+// Enqueue persists message as pending. Enqueue of the same operation with the same Id again is no-op,
+// so it is safe to retry Enqueue
+func (o *Outbox) Enqueue(ctx context.Context, message Message) error {
+	if message.Id == "" {
+		return ErrEmptyId
+	}
+	switch message.Kind {
+	case KindCapture, KindVoid, KindRefund:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownKind, message.Kind)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	existing, ok, err := o.store.Get(ctx, message.Id)
+	if err != nil {
+		return err
+	}
+	if ok {
+		if !existing.same(message) {
+			return fmt.Errorf("%w: %s", ErrIdReused, message.Id)
+		}
+		return nil
+	}
+
+	now := o.now()
+	message.Status = StatusPending
+	message.Attempts = 0
+	message.LastError = ""
+	message.Response = nil
+	message.CreatedAt = now
+	message.UpdatedAt = now
+
+	return o.store.Put(ctx, message)
+}
+
+// Get returns the message with its current status and the gateway response when it is done
+func (o *Outbox) Get(ctx context.Context, id string) (Message, bool, error) {
+	return o.store.Get(ctx, id)
+}
+
+// Dispatch sends all unfinished messages once and returns the number of processed messages.
+// Errors of the calls are recorded in messages, only store errors are returned
+func (o *Outbox) Dispatch(ctx context.Context) (int, error) {
+	messages, err := o.store.Unfinished(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, m := range messages {
+		if err = ctx.Err(); err != nil {
+			return i, err
+		}
+		if err = o.dispatch(ctx, m); err != nil {
+			return i, err
+		}
+	}
+	return len(messages), nil
+}
+
+// Run calls Dispatch every interval until ctx is done
+func (o *Outbox) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := o.Dispatch(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (o *Outbox) dispatch(ctx context.Context, m Message) error {
+	m.Status = StatusSending
+	m.Attempts++
+	m.UpdatedAt = o.now()
+	if err := o.store.Put(ctx, m); err != nil {
+		return err
+	}
+
+	tr, err := o.send(ctx, m)
+
+	m.UpdatedAt = o.now()
+	switch {
+	case err == nil:
+		m.Status = StatusDone
+		m.LastError = ""
+		m.Response = &tr
+	case errors.Is(err, idempotency.ErrKeyReused) || m.Attempts >= o.maxAttempts:
+		m.Status = StatusFailed
+		m.LastError = err.Error()
+	default:
+		m.Status = StatusPending
+		m.LastError = err.Error()
+	}
+
+	return o.store.Put(ctx, m)
+}
+
+func (o *Outbox) send(ctx context.Context, m Message) (vo.TransactionResponse, error) {
+	switch m.Kind {
+	case KindCapture:
+		r := vo.CaptureRequest{}
+		r.Request.ParentUid = m.ParentUid
+		r.Request.Amount = m.Amount
+		return o.executor.Capture(ctx, m.Id, r)
+	case KindVoid:
+		return o.executor.Void(ctx, m.Id, *vo.NewVoidRequest(m.ParentUid, m.Amount))
+	case KindRefund:
+		return o.executor.Refund(ctx, m.Id, *vo.NewRefundRequest(m.ParentUid, m.Amount, m.Reason))
+	}
+	return vo.TransactionResponse{}, fmt.Errorf("%w: %q", ErrUnknownKind, m.Kind)
+}
@@ -0,0 +1,79 @@
+package outbox
+
+import (
+	"bepaid-sdk/service/vo"
+	"context"
+	"time"
+)
+
+type Kind string
+
+const (
+	KindCapture Kind = "capture"
+	KindVoid    Kind = "void"
+	KindRefund  Kind = "refund"
+)
+
+type Status string
+
+const (
+	// StatusPending is waiting for the first or the next attempt
+	StatusPending Status = "pending"
+
+	// StatusSending is set before the call, message left in this status after a crash has unknown outcome
+	// and is resolved by lookup by tracking_id on the next attempt
+	StatusSending Status = "sending"
+
+	// StatusDone has the gateway response, declines are also done
+	StatusDone Status = "done"
+
+	// StatusFailed ran out of attempts or can't be sent at all, it needs manual check
+	StatusFailed Status = "failed"
+)
+
+// Message is a money-moving operation persisted before it is sent to the gateway.
+//
+// Payments and authorizations are not supported, because their requests contain card data
+type Message struct {
+	// Id is the idempotency key of the operation, e.g. id of refund in the local system
+	Id string `json:"id"`
+
+	Kind      Kind   `json:"kind"`
+	ParentUid string `json:"parent_uid"`
+
+	//сумма в минимальных денежных единицах
+	Amount int64 `json:"amount"`
+
+	//причина возврата, обязательна для refund
+	Reason string `json:"reason,omitempty"`
+
+	Status    Status `json:"status"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error,omitempty"`
+
+	//ответ шлюза, если Status == done
+	Response *vo.TransactionResponse `json:"response,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// same reports whether m and other describe the same operation
+func (m Message) same(other Message) bool {
+	return m.Kind == other.Kind && m.ParentUid == other.ParentUid && m.Amount == other.Amount && m.Reason == other.Reason
+}
+
+func (m Message) finished() bool {
+	return m.Status == StatusDone || m.Status == StatusFailed
+}
+
+// Store persists messages. Implementations must be safe for concurrent use
+type Store interface {
+	Get(ctx context.Context, id string) (message Message, ok bool, err error)
+
+	// Put creates or replaces message with message.Id
+	Put(ctx context.Context, message Message) error
+
+	// Unfinished returns pending and sending messages ordered by CreatedAt
+	Unfinished(ctx context.Context) ([]Message, error)
+}
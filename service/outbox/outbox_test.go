@@ -0,0 +1,155 @@
+package outbox
+
+import (
+	"bepaid-sdk/service/idempotency"
+	"bepaid-sdk/testdata"
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
+
+const (
+	parent   = `{"transaction":{"uid":"1-a","type":"payment","status":"successful","amount":100,"tracking_id":"order-1"}}`
+	refunded = `{"transaction":{"uid":"2-a","parent_uid":"1-a","type":"refund","status":"successful","amount":40,"tracking_id":"order-1"}}`
+	before   = `{"transactions":[{"uid":"1-a","type":"payment","status":"successful","amount":100,"tracking_id":"order-1"}]}`
+	after    = `{"transactions":[{"uid":"1-a","type":"payment","status":"successful","amount":100,"tracking_id":"order-1"},
+		{"uid":"2-a","parent_uid":"1-a","type":"refund","status":"successful","amount":40,"tracking_id":"order-1"}]}`
+)
+
+func refund() Message {
+	return Message{Id: "refund-1", Kind: KindRefund, ParentUid: "1-a", Amount: 40, Reason: "return"}
+}
+
+func TestOutbox_Enqueue(t *testing.T) {
+	ctx := context.Background()
+	o := New(nil, NewMemoryStore(), idempotency.NewMemoryStore())
+
+	assert.ErrorIs(t, o.Enqueue(ctx, Message{Kind: KindRefund}), ErrEmptyId)
+	assert.ErrorIs(t, o.Enqueue(ctx, Message{Id: "payment-1", Kind: "payment"}), ErrUnknownKind)
+
+	assert.NoError(t, o.Enqueue(ctx, refund()))
+	assert.NoError(t, o.Enqueue(ctx, refund()))
+
+	other := refund()
+	other.Amount = 50
+	assert.ErrorIs(t, o.Enqueue(ctx, other), ErrIdReused)
+
+	m, ok, err := o.Get(ctx, "refund-1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, StatusPending, m.Status)
+	assert.Equal(t, int64(40), m.Amount)
+}
+
+func TestOutbox_UnknownOutcomeResolvedByTrackingId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	api := testdata.NewMockApi(ctrl)
+	gomock.InOrder(
+		api.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(jsonResponse(parent), nil),
+		api.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(jsonResponse(before), nil),
+		api.EXPECT().Refund(gomock.Any(), gomock.Any()).Return(nil, errors.New("timeout")),
+		api.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(nil, errors.New("timeout")),
+		// next dispatch finds the refund and doesn't send it again
+		api.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(jsonResponse(after), nil),
+	)
+
+	o := New(api, NewMemoryStore(), idempotency.NewMemoryStore())
+	assert.NoError(t, o.Enqueue(ctx, refund()))
+
+	n, err := o.Dispatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	m, _, _ := o.Get(ctx, "refund-1")
+	assert.Equal(t, StatusPending, m.Status)
+	assert.Equal(t, "timeout", m.LastError)
+
+	_, err = o.Dispatch(ctx)
+	assert.NoError(t, err)
+
+	m, _, _ = o.Get(ctx, "refund-1")
+	assert.Equal(t, StatusDone, m.Status)
+	assert.Equal(t, 2, m.Attempts)
+	assert.Equal(t, "2-a", m.Response.Transaction.Uid)
+
+	n, err = o.Dispatch(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestOutbox_FailedAfterMaxAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(nil, errors.New("unavailable")).Times(2)
+
+	o := New(api, NewMemoryStore(), idempotency.NewMemoryStore()).WithMaxAttempts(2)
+	assert.NoError(t, o.Enqueue(ctx, refund()))
+
+	for i := 0; i < 3; i++ {
+		_, err := o.Dispatch(ctx)
+		assert.NoError(t, err)
+	}
+
+	m, _, _ := o.Get(ctx, "refund-1")
+	assert.Equal(t, StatusFailed, m.Status)
+	assert.Equal(t, "unavailable", m.LastError)
+}
+
+func TestOutbox_FileStoreSurvivesRestart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	messages, err := NewFileStore(dir + "/messages")
+	assert.NoError(t, err)
+	records, err := idempotency.NewFileStore(dir + "/records")
+	assert.NoError(t, err)
+
+	assert.NoError(t, New(nil, messages, records).Enqueue(ctx, refund()))
+
+	// restart
+	messages, err = NewFileStore(dir + "/messages")
+	assert.NoError(t, err)
+
+	unfinished, err := messages.Unfinished(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, unfinished, 1)
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-a").Return(jsonResponse(parent), nil)
+	api.EXPECT().StatusByTrackingId(gomock.Any(), "order-1").Return(jsonResponse(before), nil)
+	api.EXPECT().Refund(gomock.Any(), gomock.Any()).Return(jsonResponse(refunded), nil)
+
+	_, err = New(api, messages, records).Dispatch(ctx)
+	assert.NoError(t, err)
+
+	m, ok, err := messages.Get(ctx, "refund-1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, StatusDone, m.Status)
+	assert.Equal(t, "2-a", m.Response.Transaction.Uid)
+
+	unfinished, err = messages.Unfinished(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, unfinished)
+}
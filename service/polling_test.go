@@ -0,0 +1,134 @@
+package service
+
+import (
+	"bepaid-sdk/service/vo"
+	"bepaid-sdk/testdata"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	incompleteStatus = `{"transaction":{"uid":"1-310b0da80b","status":"incomplete","type":"payment"}}`
+	successfulStatus = `{"transaction":{"uid":"1-310b0da80b","status":"successful","type":"payment"}}`
+)
+
+// fakeClock fires immediately and records requested delays
+type fakeClock struct {
+	delays []time.Duration
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	c.delays = append(c.delays, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch, func() bool { return false }
+}
+
+// blockingClock never fires
+type blockingClock struct{}
+
+func (blockingClock) NewTimer(time.Duration) (<-chan time.Time, func() bool) {
+	return nil, func() bool { return true }
+}
+
+// stoppedClock never fires and counts stopped timers
+type stoppedClock struct {
+	stopped int
+}
+
+func (c *stoppedClock) NewTimer(time.Duration) (<-chan time.Time, func() bool) {
+	return nil, func() bool {
+		c.stopped++
+		return true
+	}
+}
+
+func TestApiService_WaitForFinalStatusBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	gomock.InOrder(
+		api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(incompleteStatus), nil),
+		api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(incompleteStatus), nil),
+		api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(incompleteStatus), nil),
+		api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(incompleteStatus), nil),
+		api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(successfulStatus), nil),
+	)
+
+	clock := &fakeClock{}
+	tr, err := NewApiService(api).WaitForFinalStatus(context.Background(), "1-310b0da80b", WaitOptions{
+		InitialInterval: time.Second,
+		MaxInterval:     5 * time.Second,
+		Multiplier:      2,
+		Clock:           clock,
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, tr.IsSuccess())
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}, clock.delays)
+}
+
+func TestApiService_WaitForFinalStatusContextCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").DoAndReturn(func(context.Context, string) (*http.Response, error) {
+		cancel()
+		return jsonResponse(incompleteStatus), nil
+	})
+
+	tr, err := NewApiService(api).WaitForFinalStatus(ctx, "1-310b0da80b", WaitOptions{Clock: blockingClock{}})
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, tr.IsIncomplete())
+}
+
+func TestApiService_WaitForFinalStatusNotifier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(incompleteStatus), nil)
+
+	notifier := make(chan vo.TransactionResponse, 2)
+	other := vo.TransactionResponse{}
+	other.Transaction.Uid = "2-310b0da80b"
+	other.Transaction.Status = "successful"
+	final := vo.TransactionResponse{}
+	final.Transaction.Uid = "1-310b0da80b"
+	final.Transaction.Status = "failed"
+	notifier <- other
+	notifier <- final
+
+	clock := &stoppedClock{}
+	tr, err := NewApiService(api).WaitForFinalStatus(context.Background(), "1-310b0da80b", WaitOptions{
+		Notifier: notifier,
+		Clock:    clock,
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, tr.IsFailed())
+	assert.Equal(t, 1, clock.stopped)
+}
+
+func TestApiService_WaitForFinalStatusErrorResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	api := testdata.NewMockApi(ctrl)
+	api.EXPECT().StatusByUid(gomock.Any(), "1-310b0da80b").Return(jsonResponse(`{"response":{"message":"Record not found"}}`), nil)
+
+	_, err := NewApiService(api).WaitForFinalStatus(context.Background(), "1-310b0da80b", WaitOptions{Clock: blockingClock{}})
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,64 @@
+package receipt
+
+import (
+	"strconv"
+	"strings"
+)
+
+// minorUnits of currencies without 2 digits after the decimal point, ISO 4217
+var minorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// MinorUnits returns the number of digits after the decimal point of currency. Unknown currencies have 2
+func MinorUnits(currency string) int {
+	if n, ok := minorUnits[strings.ToUpper(currency)]; ok {
+		return n
+	}
+	return 2
+}
+
+// FormatAmount formats amount in minor units of currency, e.g. 123456 BYN is "1 234,56 BYN" in Russian
+// and Belarusian and "1,234.56 BYN" in English. Groups of Russian and Belarusian amounts are separated with
+// non-breaking space, so the amount is never wrapped
+func FormatAmount(amount int64, currency string, lang Language) string {
+	sign := ""
+	// unsigned negation, -math.MinInt64 overflows int64
+	abs := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		abs = -abs
+	}
+
+	units := MinorUnits(currency)
+	digits := strconv.FormatUint(abs, 10)
+	if len(digits) <= units {
+		digits = strings.Repeat("0", units-len(digits)+1) + digits
+	}
+	whole, fraction := digits[:len(digits)-units], digits[len(digits)-units:]
+
+	decimal, group := ",", "\u00a0"
+	if lang == English {
+		decimal, group = ".", ","
+	}
+
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(group)
+		}
+		b.WriteRune(d)
+	}
+	if units > 0 {
+		b.WriteString(decimal)
+		b.WriteString(fraction)
+	}
+	if currency != "" {
+		b.WriteString(" ")
+		b.WriteString(strings.ToUpper(currency))
+	}
+	return b.String()
+}
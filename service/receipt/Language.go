@@ -0,0 +1,120 @@
+package receipt
+
+import (
+	"bepaid-sdk/service/vo"
+	"strings"
+)
+
+// Language is the language of requests, so language of a payment can be used for its receipt
+type Language = vo.Language
+
+const (
+	Russian    = vo.LanguageRussian
+	Belarusian = vo.LanguageBelarusian
+	English    = vo.LanguageEnglish
+)
+
+// ParseLanguage accepts language tags like "be", "ru-RU" or "en_US". Unknown languages are English
+func ParseLanguage(tag string) Language {
+	tag = strings.ToLower(tag)
+	for _, l := range []Language{Russian, Belarusian, English} {
+		if tag == string(l) || strings.HasPrefix(tag, string(l)+"-") || strings.HasPrefix(tag, string(l)+"_") {
+			return l
+		}
+	}
+	return English
+}
+
+// labels of receipts, keys are used by templates as {{index .Labels "amount"}}
+var labels = map[Language]map[string]string{
+	Russian: {
+		"receipt":       "Чек",
+		"merchant":      "Продавец",
+		"tax_id":        "УНП",
+		"date":          "Дата",
+		"transaction":   "Транзакция",
+		"order":         "Заказ",
+		"description":   "Описание",
+		"card":          "Карта",
+		"holder":        "Держатель",
+		"auth_code":     "Код авторизации",
+		"rrn":           "RRN",
+		"amount":        "Сумма",
+		"status":        "Статус",
+		"test":          "ТЕСТОВАЯ ОПЕРАЦИЯ",
+		"payment":       "Оплата",
+		"authorization": "Авторизация",
+		"capture":       "Списание",
+		"void":          "Отмена",
+		"refund":        "Возврат",
+		"successful":    "Успешно",
+		"failed":        "Отклонено",
+		"incomplete":    "Не завершено",
+		"expired":       "Истекло",
+	},
+	Belarusian: {
+		"receipt":       "Чэк",
+		"merchant":      "Прадавец",
+		"tax_id":        "УНП",
+		"date":          "Дата",
+		"transaction":   "Транзакцыя",
+		"order":         "Заказ",
+		"description":   "Апісанне",
+		"card":          "Картка",
+		"holder":        "Трымальнік",
+		"auth_code":     "Код аўтарызацыі",
+		"rrn":           "RRN",
+		"amount":        "Сума",
+		"status":        "Статус",
+		"test":          "ТЭСТАВАЯ АПЕРАЦЫЯ",
+		"payment":       "Аплата",
+		"authorization": "Аўтарызацыя",
+		"capture":       "Спісанне",
+		"void":          "Адмена",
+		"refund":        "Вяртанне",
+		"successful":    "Паспяхова",
+		"failed":        "Адхілена",
+		"incomplete":    "Не завершана",
+		"expired":       "Скончыўся тэрмін",
+	},
+	English: {
+		"receipt":       "Receipt",
+		"merchant":      "Merchant",
+		"tax_id":        "Tax ID",
+		"date":          "Date",
+		"transaction":   "Transaction",
+		"order":         "Order",
+		"description":   "Description",
+		"card":          "Card",
+		"holder":        "Cardholder",
+		"auth_code":     "Authorization code",
+		"rrn":           "RRN",
+		"amount":        "Amount",
+		"status":        "Status",
+		"test":          "TEST TRANSACTION",
+		"payment":       "Payment",
+		"authorization": "Authorization",
+		"capture":       "Capture",
+		"void":          "Void",
+		"refund":        "Refund",
+		"successful":    "Successful",
+		"failed":        "Declined",
+		"incomplete":    "Incomplete",
+		"expired":       "Expired",
+	},
+}
+
+// dateLayouts of receipt time
+var dateLayouts = map[Language]string{
+	Russian:    "02.01.2006 15:04:05",
+	Belarusian: "02.01.2006 15:04:05",
+	English:    "02 Jan 2006 15:04:05",
+}
+
+// translate returns the label of key, or key itself if there is no such label
+func translate(lang Language, key string) string {
+	if l, ok := labels[lang][key]; ok {
+		return l
+	}
+	return key
+}
@@ -0,0 +1,104 @@
+package receipt
+
+import (
+	"bepaid-sdk/service/vo"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrNoTransaction = errors.New("receipt: response has no transaction")
+
+// Merchant details printed in the header of receipt
+type Merchant struct {
+	Name    string
+	Address string
+
+	//УНП (ИНН)
+	TaxId string
+
+	Phone   string
+	Website string
+}
+
+// Receipt is the data of a receipt taken from a transaction
+type Receipt struct {
+	Merchant Merchant
+
+	Uid        string
+	TrackingId string
+	Type       string
+	Status     string
+
+	Description string
+
+	//сумма в минимальных денежных единицах
+	Amount   int64
+	Currency string
+
+	// Card is the masked card number, e.g. "4*** **** **** 0000"
+	Card       string
+	CardBrand  string
+	CardHolder string
+
+	AuthCode string
+	Rrn      string
+
+	// Time is the payment time if known, otherwise the transaction creation time
+	Time time.Time
+
+	Test bool
+}
+
+// FromTransaction builds receipt of the response transaction. Card data is masked, only the first digit
+// and the last four are kept
+func FromTransaction(tr vo.TransactionResponse, merchant Merchant) (Receipt, error) {
+	if tr.IsError() || tr.Transaction.Uid == "" {
+		if tr.Response.Message != "" {
+			return Receipt{}, fmt.Errorf("%w: %s", ErrNoTransaction, tr.Response.Message)
+		}
+		return Receipt{}, ErrNoTransaction
+	}
+
+	t := tr.Transaction
+	r := Receipt{
+		Merchant:    merchant,
+		Uid:         t.Uid,
+		TrackingId:  t.TrackingId,
+		Type:        t.Type,
+		Status:      t.Status,
+		Description: t.Description,
+		Amount:      t.Amount,
+		Currency:    t.Currency,
+		Time:        t.CreatedAt,
+		Test:        t.Test,
+	}
+	if t.PaidAt != nil {
+		r.Time = *t.PaidAt
+	}
+
+	if c := t.CreditCard; c != nil {
+		r.Card = MaskCard(c.First1, c.Last4)
+		r.CardBrand = c.Brand
+		r.CardHolder = c.Holder
+	}
+
+	if p := t.ProcessingResult(); p != nil {
+		r.AuthCode = p.AuthCode
+		r.Rrn = p.Rrn
+	}
+
+	return r, nil
+}
+
+// MaskCard returns masked card number of 16 digits, e.g. "4*** **** **** 0000". Unknown first digit is masked too
+func MaskCard(first1, last4 string) string {
+	if last4 == "" {
+		return ""
+	}
+	first := "*"
+	if len(first1) == 1 {
+		first = first1
+	}
+	return first + "*** **** **** " + last4
+}
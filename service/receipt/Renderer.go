@@ -0,0 +1,141 @@
+package receipt
+
+import (
+	"embed"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// textWidth of plain text receipts in characters, 80 mm receipt printers print 40-48
+const textWidth = 40
+
+//go:embed templates
+var templates embed.FS
+
+var (
+	textTemplate = texttemplate.Must(texttemplate.New("receipt.txt.tmpl").Funcs(texttemplate.FuncMap{
+		"row":    row,
+		"center": center,
+		"rule":   func() string { return strings.Repeat("-", textWidth) },
+	}).ParseFS(templates, "templates/receipt.txt.tmpl"))
+
+	htmlTemplate = htmltemplate.Must(htmltemplate.ParseFS(templates, "templates/receipt.html.tmpl"))
+)
+
+// Renderer renders receipts in one language:
+//
+//	r, err := receipt.FromTransaction(tr, receipt.Merchant{Name: "Shop", TaxId: "190000000"})
+//	err = receipt.NewRenderer(receipt.Belarusian).Text(w, r)
+type Renderer struct {
+	lang     Language
+	location *time.Location
+}
+
+func NewRenderer(lang Language) *Renderer {
+	if _, ok := labels[lang]; !ok {
+		lang = English
+	}
+	return &Renderer{lang: lang, location: time.Local}
+}
+
+// WithLocation sets time zone of receipt time. Default is time.Local
+func (r *Renderer) WithLocation(location *time.Location) *Renderer {
+	r.location = location
+	return r
+}
+
+// Text renders plain text receipt for receipt printers and emails
+func (r *Renderer) Text(w io.Writer, receipt Receipt) error {
+	return textTemplate.Execute(w, r.view(receipt))
+}
+
+// HTML renders receipt page, it prints on 80 mm paper without PDF conversion
+func (r *Renderer) HTML(w io.Writer, receipt Receipt) error {
+	return htmlTemplate.Execute(w, r.view(receipt))
+}
+
+// view is the data of templates
+type view struct {
+	Receipt
+
+	Language        Language
+	Labels          map[string]string
+	Title           string
+	Time            string
+	StatusLabel     string
+	FormattedAmount string
+}
+
+func (r *Renderer) view(receipt Receipt) view {
+	v := view{
+		Receipt:         receipt,
+		Language:        r.lang,
+		Labels:          labels[r.lang],
+		Title:           translate(r.lang, "receipt"),
+		StatusLabel:     translate(r.lang, strings.ToLower(receipt.Status)),
+		FormattedAmount: FormatAmount(receipt.Amount, receipt.Currency, r.lang),
+	}
+	if receipt.Type != "" {
+		v.Title += ": " + translate(r.lang, strings.ToLower(receipt.Type))
+	}
+	if !receipt.Time.IsZero() {
+		v.Time = receipt.Time.In(r.location).Format(dateLayouts[r.lang])
+	}
+	return v
+}
+
+// row aligns value to the right edge. Too long value is moved to the next lines, see wrap
+func row(label, value string) string {
+	gap := textWidth - utf8.RuneCountInString(label) - utf8.RuneCountInString(value)
+	if gap >= 1 {
+		return label + strings.Repeat(" ", gap) + value
+	}
+
+	lines := wrap(label, textWidth)
+	for _, line := range wrap(value, textWidth) {
+		lines = append(lines, strings.Repeat(" ", textWidth-utf8.RuneCountInString(line))+line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func center(s string) string {
+	lines := wrap(s, textWidth)
+	for i, line := range lines {
+		lines[i] = strings.Repeat(" ", (textWidth-utf8.RuneCountInString(line))/2) + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrap splits s into lines of at most width characters at spaces, longer words like uids are split anywhere.
+// Non-breaking spaces of amounts are kept
+func wrap(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Split(s, " ") {
+		if word == "" {
+			continue
+		}
+		if line != "" && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width {
+			line += " " + word
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		for runes := []rune(word); ; runes = runes[width:] {
+			if len(runes) <= width {
+				line = string(runes)
+				break
+			}
+			lines = append(lines, string(runes[:width]))
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,141 @@
+package receipt
+
+import (
+	"bepaid-sdk/service/vo"
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func payment() vo.TransactionResponse {
+	paidAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	return vo.TransactionResponse{Transaction: vo.Transaction{
+		Uid:         "1-a",
+		TrackingId:  "order-1",
+		Type:        "payment",
+		Status:      "successful",
+		Description: "Заказ <№1>",
+		Amount:      123456,
+		Currency:    "BYN",
+		CreatedAt:   paidAt.Add(-time.Minute),
+		PaidAt:      &paidAt,
+		CreditCard:  &vo.CreditCardInfo{Holder: "IVAN IVANOV", Brand: "visa", First1: "4", Last4: "0000", Token: "tok_123"},
+		Payment:     &vo.ProcessingResult{AuthCode: "654321", Rrn: "123456789012"},
+		Test:        true,
+	}}
+}
+
+var merchant = Merchant{Name: "ООО Магазин", Address: "Минск, пр. Независимости, 1", TaxId: "190000000"}
+
+func TestFromTransaction(t *testing.T) {
+	r, err := FromTransaction(payment(), merchant)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "4*** **** **** 0000", r.Card)
+	assert.Equal(t, "654321", r.AuthCode)
+	assert.Equal(t, "123456789012", r.Rrn)
+	assert.Equal(t, 9, r.Time.Hour())
+	assert.Equal(t, int64(123456), r.Amount)
+
+	_, err = FromTransaction(vo.TransactionResponse{Response: vo.ErrorResponse{Message: "Unauthorized"}}, merchant)
+	assert.ErrorIs(t, err, ErrNoTransaction)
+}
+
+func TestRenderer_Text(t *testing.T) {
+	r, _ := FromTransaction(payment(), merchant)
+	minsk := time.FixedZone("Europe/Minsk", 3*60*60)
+
+	var b bytes.Buffer
+	assert.NoError(t, NewRenderer(Russian).WithLocation(minsk).Text(&b, r))
+	text := b.String()
+
+	assert.Contains(t, text, "УНП 190000000")
+	assert.Contains(t, text, "Чек: Оплата")
+	assert.Contains(t, text, "ТЕСТОВАЯ ОПЕРАЦИЯ")
+	assert.Contains(t, text, "01.03.2024 12:30:00")
+	assert.Contains(t, text, "visa 4*** **** **** 0000")
+	assert.Contains(t, text, "Код авторизации")
+	assert.Contains(t, text, "Успешно")
+	assert.Contains(t, text, "Заказ <№1>")
+	assert.NotContains(t, text, "tok_123")
+
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		assert.LessOrEqual(t, len([]rune(line)), textWidth, line)
+	}
+	amount := "1\u00a0234,56 BYN"
+	assert.Contains(t, text, "Сумма"+strings.Repeat(" ", textWidth-len([]rune("Сумма"+amount)))+amount+"\n")
+}
+
+func TestRenderer_TextWrapsLongValues(t *testing.T) {
+	r, _ := FromTransaction(payment(), Merchant{Name: "Общество с ограниченной ответственностью «Очень длинное название магазина»"})
+	r.Description = "Подписка на онлайн-кинотеатр на 12 месяцев с автоматическим продлением"
+	r.Uid = strings.Repeat("0123456789", 5) + "-abcdef"
+
+	var b bytes.Buffer
+	assert.NoError(t, NewRenderer(Russian).WithLocation(time.UTC).Text(&b, r))
+	text := b.String()
+
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		assert.LessOrEqual(t, len([]rune(line)), textWidth, line)
+	}
+	assert.True(t, strings.HasPrefix(text, "Общество с ограниченной ответственностью\n   «Очень длинное название магазина»\n"))
+	assert.Contains(t, text, "Описание\n")
+	assert.Contains(t, text, "\n      Подписка на онлайн-кинотеатр на 12\n     месяцев с автоматическим продлением\n")
+	assert.Contains(t, text, "\n"+strings.Repeat("0123456789", 4)+"\n")
+	assert.Contains(t, text, "\n"+strings.Repeat(" ", 23)+"0123456789-abcdef\n")
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{""}, wrap("", 10))
+	assert.Equal(t, []string{"one two", "three"}, wrap("one  two three", 9))
+	assert.Equal(t, []string{"a", "0123", "4567", "89 b"}, wrap("a 0123456789 b", 4))
+	assert.Equal(t, []string{"1\u00a0234,56", "BYN"}, wrap("1\u00a0234,56 BYN", 9))
+}
+
+func TestRenderer_Languages(t *testing.T) {
+	r, _ := FromTransaction(payment(), Merchant{})
+	r.Type, r.Status = "refund", "failed"
+
+	for lang, expected := range map[Language][]string{
+		Belarusian: {"Чэк: Вяртанне", "Адхілена", "Сума", "1\u00a0234,56 BYN"},
+		English:    {"Receipt: Refund", "Declined", "Amount", "1,234.56 BYN", "01 Mar 2024 09:30:00"},
+	} {
+		var b bytes.Buffer
+		assert.NoError(t, NewRenderer(lang).WithLocation(time.UTC).Text(&b, r))
+		for _, s := range expected {
+			assert.Contains(t, b.String(), s, lang)
+		}
+	}
+}
+
+func TestRenderer_HTML(t *testing.T) {
+	r, _ := FromTransaction(payment(), merchant)
+
+	var b bytes.Buffer
+	assert.NoError(t, NewRenderer(Belarusian).HTML(&b, r))
+	html := b.String()
+
+	assert.Contains(t, html, `<html lang="be">`)
+	assert.Contains(t, html, "Заказ &lt;№1&gt;")
+	assert.Contains(t, html, "Код аўтарызацыі")
+	assert.Contains(t, html, "@media print")
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "0,05 BYN", FormatAmount(5, "BYN", Russian))
+	assert.Equal(t, "-12,00 RUB", FormatAmount(-1200, "RUB", Belarusian))
+	assert.Equal(t, "1,500 JPY", FormatAmount(1500, "jpy", English))
+	assert.Equal(t, "1.234 KWD", FormatAmount(1234, "KWD", English))
+	assert.Equal(t, "1\u00a0000\u00a0000,00 USD", FormatAmount(100000000, "USD", Russian))
+	assert.Equal(t, "-92,233,720,368,547,758.08 USD", FormatAmount(math.MinInt64, "USD", English))
+}
+
+func TestParseLanguage(t *testing.T) {
+	assert.Equal(t, Belarusian, ParseLanguage("be-BY"))
+	assert.Equal(t, Russian, ParseLanguage("ru_RU"))
+	assert.Equal(t, English, ParseLanguage("de"))
+}
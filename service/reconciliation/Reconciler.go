@@ -0,0 +1,233 @@
+package reconciliation
+
+import (
+	"bepaid-sdk/service"
+	"bepaid-sdk/service/contracts"
+	"bepaid-sdk/service/vo"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+const defaultWorkers = 4
+
+// Order is a payment as it is recorded in the local system
+type Order struct {
+	TrackingId string `json:"tracking_id"`
+
+	//сумма в минимальных денежных единицах
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+
+	// ExpectedStatus is either transaction status (successful, failed, incomplete, expired)
+	// or lifecycle state (authorized, voided, captured, partially_refunded, refunded).
+	// Empty ExpectedStatus is not checked
+	ExpectedStatus string `json:"expected_status"`
+}
+
+// Reconciler compares local orders with gateway transactions found by tracking_id
+type Reconciler struct {
+	service contracts.ApiService
+	workers int
+}
+
+func NewReconciler(service contracts.ApiService) *Reconciler {
+	return &Reconciler{service: service, workers: defaultWorkers}
+}
+
+// WithWorkers sets the number of concurrent status requests. Default is 4
+func (r *Reconciler) WithWorkers(workers int) *Reconciler {
+	if workers < 1 {
+		workers = 1
+	}
+	r.workers = workers
+	return r
+}
+
+// Reconcile reads orders until the channel is closed.
+//
+// If ctx is done, the report of already checked orders is returned with ctx.Err()
+func (r *Reconciler) Reconcile(ctx context.Context, orders <-chan Order) (Report, error) {
+	jobs := make(chan Order)
+	results := make(chan []Mismatch)
+
+	var wg sync.WaitGroup
+	for i := 0; i < r.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for o := range jobs {
+				results <- r.check(ctx, o)
+			}
+		}()
+	}
+
+	report := Report{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for m := range results {
+			report.Checked++
+			if len(m) == 0 {
+				report.Matched++
+			}
+			report.Mismatches = append(report.Mismatches, m...)
+		}
+	}()
+
+	err := r.dispatch(ctx, orders, jobs, results)
+
+	close(jobs)
+	wg.Wait()
+	close(results)
+	<-done
+
+	report.sort()
+	return report, err
+}
+
+// dispatch sends orders to workers. Orders without tracking_id or with already seen one are reported without request
+func (r *Reconciler) dispatch(ctx context.Context, orders <-chan Order, jobs chan<- Order, results chan<- []Mismatch) error {
+	seen := map[string]struct{}{}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case o, ok := <-orders:
+			if !ok {
+				return nil
+			}
+
+			if o.TrackingId == "" {
+				results <- []Mismatch{mismatch(MismatchNoTrackingId, o)}
+				continue
+			}
+			if _, ok := seen[o.TrackingId]; ok {
+				m := mismatch(MismatchDuplicate, o)
+				m.Message = "duplicate tracking_id in local orders"
+				results <- []Mismatch{m}
+				continue
+			}
+			seen[o.TrackingId] = struct{}{}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case jobs <- o:
+			}
+		}
+	}
+}
+
+func (r *Reconciler) check(ctx context.Context, o Order) []Mismatch {
+	tr, err := r.service.StatusByTrackingId(ctx, o.TrackingId)
+
+	var statusErr *service.StatusError
+	switch {
+	case errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound,
+		err == nil && tr.Response.StatusCode == http.StatusNotFound:
+		// the gateway doesn't know the tracking_id, the same answer makes api.Ping succeed.
+		// An empty list of transactions is missing too, see compare
+		return []Mismatch{mismatch(MismatchMissing, o)}
+	case err == nil && tr.Response.Message != "":
+		m := mismatch(MismatchError, o)
+		m.Message = tr.Response.Message
+		return []Mismatch{m}
+	case err != nil:
+		m := mismatch(MismatchError, o)
+		m.Message = err.Error()
+		return []Mismatch{m}
+	}
+
+	return compare(o, tr.Transactions)
+}
+
+// compare checks payment or authorization of the order against o
+func compare(o Order, transactions []vo.Transaction) []Mismatch {
+	var roots, successful []vo.Transaction
+	for _, t := range transactions {
+		if !t.IsPayment() && !t.IsAuthorization() {
+			continue
+		}
+		roots = append(roots, t)
+		if t.IsSuccess() {
+			successful = append(successful, t)
+		}
+	}
+
+	if len(roots) == 0 {
+		return []Mismatch{mismatch(MismatchMissing, o)}
+	}
+
+	var result []Mismatch
+
+	root := roots[len(roots)-1]
+	if len(successful) > 0 {
+		root = successful[0]
+	}
+
+	if len(successful) > 1 {
+		m := withActual(mismatch(MismatchDuplicate, o), root)
+		m.Uids = uids(successful)
+		m.Message = fmt.Sprintf("%d successful transactions", len(successful))
+		result = append(result, m)
+	}
+
+	if root.Amount != o.Amount || root.Currency != o.Currency {
+		result = append(result, withActual(mismatch(MismatchAmount, o), root))
+	}
+
+	if o.ExpectedStatus != "" {
+		actual := root.Status
+		if isState(o.ExpectedStatus) {
+			actual = string(vo.NewBalance(root, transactions).State())
+		}
+
+		if actual != o.ExpectedStatus {
+			m := withActual(mismatch(MismatchStatus, o), root)
+			m.ActualStatus = actual
+			result = append(result, m)
+		}
+	}
+
+	return result
+}
+
+func mismatch(kind MismatchKind, o Order) Mismatch {
+	return Mismatch{
+		Kind:             kind,
+		TrackingId:       o.TrackingId,
+		ExpectedAmount:   o.Amount,
+		ExpectedCurrency: o.Currency,
+		ExpectedStatus:   o.ExpectedStatus,
+	}
+}
+
+func withActual(m Mismatch, t vo.Transaction) Mismatch {
+	m.ActualAmount = t.Amount
+	m.ActualCurrency = t.Currency
+	m.ActualStatus = t.Status
+	if m.Uids == nil {
+		m.Uids = []string{t.Uid}
+	}
+	return m
+}
+
+func uids(transactions []vo.Transaction) []string {
+	result := make([]string, 0, len(transactions))
+	for _, t := range transactions {
+		result = append(result, t.Uid)
+	}
+	return result
+}
+
+func isState(status string) bool {
+	switch vo.State(status) {
+	case vo.StateAuthorized, vo.StateVoided, vo.StateCaptured, vo.StatePartiallyRefunded, vo.StateRefunded:
+		return true
+	}
+	return false
+}
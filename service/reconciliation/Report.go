@@ -0,0 +1,110 @@
+package reconciliation
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type MismatchKind string
+
+const (
+	// MismatchMissing - no payment or authorization with order's tracking_id on the gateway
+	MismatchMissing MismatchKind = "missing"
+
+	// MismatchAmount - amount or currency of the gateway transaction differs from the order
+	MismatchAmount MismatchKind = "amount_mismatch"
+
+	// MismatchStatus - status or lifecycle state differs from the expected one
+	MismatchStatus MismatchKind = "status_drift"
+
+	// MismatchDuplicate - several successful payments or authorizations for one tracking_id,
+	// or several local orders with the same tracking_id
+	MismatchDuplicate MismatchKind = "duplicate"
+
+	// MismatchNoTrackingId - local order without tracking_id, it is not checked on the gateway
+	MismatchNoTrackingId MismatchKind = "no_tracking_id"
+
+	// MismatchError - status request failed, order is not checked
+	MismatchError MismatchKind = "error"
+)
+
+type Mismatch struct {
+	Kind       MismatchKind `json:"kind"`
+	TrackingId string       `json:"tracking_id"`
+
+	ExpectedAmount   int64  `json:"expected_amount"`
+	ActualAmount     int64  `json:"actual_amount"`
+	ExpectedCurrency string `json:"expected_currency"`
+	ActualCurrency   string `json:"actual_currency,omitempty"`
+	ExpectedStatus   string `json:"expected_status"`
+	ActualStatus     string `json:"actual_status,omitempty"`
+
+	//UID транзакций шлюза, относящихся к расхождению
+	Uids []string `json:"uids,omitempty"`
+
+	Message string `json:"message,omitempty"`
+}
+
+type Report struct {
+	// Checked is the number of received orders
+	Checked int `json:"checked"`
+
+	// Matched is the number of orders without mismatches
+	Matched int `json:"matched"`
+
+	Mismatches []Mismatch `json:"mismatches"`
+}
+
+var csvHeader = []string{
+	"kind", "tracking_id",
+	"expected_amount", "actual_amount",
+	"expected_currency", "actual_currency",
+	"expected_status", "actual_status",
+	"uids", "message",
+}
+
+// WriteJSON writes indented report
+func (r Report) WriteJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(r)
+}
+
+// WriteCSV writes mismatches, one per line, with header. Uids are separated by space
+func (r Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, m := range r.Mismatches {
+		err := cw.Write([]string{
+			string(m.Kind), m.TrackingId,
+			strconv.FormatInt(m.ExpectedAmount, 10), strconv.FormatInt(m.ActualAmount, 10),
+			m.ExpectedCurrency, m.ActualCurrency,
+			m.ExpectedStatus, m.ActualStatus,
+			strings.Join(m.Uids, " "), m.Message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// sort orders mismatches by tracking_id and kind, so reports of the same data are equal
+func (r *Report) sort() {
+	sort.SliceStable(r.Mismatches, func(i, j int) bool {
+		if r.Mismatches[i].TrackingId != r.Mismatches[j].TrackingId {
+			return r.Mismatches[i].TrackingId < r.Mismatches[j].TrackingId
+		}
+		return r.Mismatches[i].Kind < r.Mismatches[j].Kind
+	})
+}
@@ -0,0 +1,128 @@
+package reconciliation
+
+import (
+	"bepaid-sdk/service"
+	"bepaid-sdk/service/vo"
+	"bepaid-sdk/testdata"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func tx(uid, typ, status, parentUid string, amount int64) vo.Transaction {
+	return vo.Transaction{Uid: uid, Type: typ, Status: status, ParentUid: parentUid, Amount: amount, Currency: "BYN"}
+}
+
+func ordersChan(orders ...Order) <-chan Order {
+	ch := make(chan Order, len(orders))
+	for _, o := range orders {
+		ch <- o
+	}
+	close(ch)
+	return ch
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gateway := map[string][]vo.Transaction{
+		"ok":       {tx("1", "payment", "successful", "", 100)},
+		"refunded": {tx("2", "authorization", "successful", "", 100), tx("3", "capture", "successful", "2", 100), tx("4", "refund", "successful", "3", 100)},
+		"amount":   {tx("5", "payment", "successful", "", 90)},
+		"drift":    {tx("6", "payment", "failed", "", 100)},
+		"twice":    {tx("7", "payment", "successful", "", 100), tx("8", "payment", "successful", "", 100)},
+		"missing":  {},
+	}
+
+	notFound := &service.StatusError{StatusCode: http.StatusNotFound}
+
+	service := testdata.NewMockApiService(ctrl)
+	for id, txs := range gateway {
+		service.EXPECT().StatusByTrackingId(gomock.Any(), id).Return(vo.TransactionsResponse{Transactions: txs}, nil)
+	}
+	service.EXPECT().StatusByTrackingId(gomock.Any(), "broken").Return(vo.TransactionsResponse{}, errors.New("timeout"))
+	service.EXPECT().StatusByTrackingId(gomock.Any(), "unknown").
+		Return(vo.TransactionsResponse{Response: vo.ErrorResponse{Message: "Record not found", StatusCode: http.StatusNotFound}}, nil)
+	service.EXPECT().StatusByTrackingId(gomock.Any(), "gone").Return(vo.TransactionsResponse{}, notFound)
+	service.EXPECT().StatusByTrackingId(gomock.Any(), "denied").
+		Return(vo.TransactionsResponse{Response: vo.ErrorResponse{Message: "Unauthorized", StatusCode: http.StatusUnauthorized}}, nil)
+
+	report, err := NewReconciler(service).WithWorkers(3).Reconcile(context.Background(), ordersChan(
+		Order{"ok", 100, "BYN", "successful"},
+		Order{"refunded", 100, "BYN", "refunded"},
+		Order{"amount", 100, "BYN", "successful"},
+		Order{"drift", 100, "BYN", "successful"},
+		Order{"twice", 100, "BYN", ""},
+		Order{"missing", 100, "BYN", "successful"},
+		Order{"broken", 100, "BYN", "successful"},
+		Order{"unknown", 100, "BYN", "successful"},
+		Order{"gone", 100, "BYN", "successful"},
+		Order{"denied", 100, "BYN", "successful"},
+		Order{"ok", 100, "BYN", "successful"},
+		Order{"", 100, "BYN", "successful"},
+		Order{"", 200, "BYN", "successful"},
+	))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 13, report.Checked)
+	assert.Equal(t, 2, report.Matched)
+
+	var kinds []string
+	for _, m := range report.Mismatches {
+		kinds = append(kinds, m.TrackingId+":"+string(m.Kind))
+	}
+	assert.Equal(t, []string{
+		":no_tracking_id",
+		":no_tracking_id",
+		"amount:amount_mismatch",
+		"broken:error",
+		"denied:error",
+		"drift:status_drift",
+		"gone:missing",
+		"missing:missing",
+		"ok:duplicate",
+		"twice:duplicate",
+		"unknown:missing",
+	}, kinds)
+
+	assert.Equal(t, int64(90), report.Mismatches[2].ActualAmount)
+	assert.Equal(t, "failed", report.Mismatches[5].ActualStatus)
+	assert.Equal(t, []string{"7", "8"}, report.Mismatches[9].Uids)
+}
+
+func TestReport_WriteCSV(t *testing.T) {
+	r := Report{Checked: 1, Mismatches: []Mismatch{{
+		Kind:             MismatchDuplicate,
+		TrackingId:       "order,1",
+		ExpectedAmount:   100,
+		ActualAmount:     100,
+		ExpectedCurrency: "BYN",
+		ActualCurrency:   "BYN",
+		ActualStatus:     "successful",
+		Uids:             []string{"7", "8"},
+		Message:          "2 successful transactions",
+	}}}
+
+	buf := bytes.Buffer{}
+	err := r.WriteCSV(&buf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "kind,tracking_id,expected_amount,actual_amount,expected_currency,actual_currency,expected_status,actual_status,uids,message\n"+
+		"duplicate,\"order,1\",100,100,BYN,BYN,,successful,7 8,2 successful transactions\n", buf.String())
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	r := Report{Checked: 1, Mismatches: []Mismatch{{Kind: MismatchMissing, TrackingId: "order-1", ExpectedAmount: 100, ExpectedCurrency: "BYN"}}}
+
+	buf := bytes.Buffer{}
+	err := r.WriteJSON(&buf)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"checked":1,"matched":0,"mismatches":[{"kind":"missing","tracking_id":"order-1","expected_amount":100,"actual_amount":0,"expected_currency":"BYN","expected_status":""}]}`, buf.String())
+}
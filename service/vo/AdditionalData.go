@@ -0,0 +1,205 @@
+package vo
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Contract is a value of additional_data.contract
+type Contract string
+
+const (
+	//карта сохраняется для последующих рекуррентных платежей без участия клиента
+	ContractRecurring Contract = "recurring"
+
+	//карта сохраняется для оплаты в один клик
+	ContractOneClick Contract = "oneclick"
+
+	//карта сохраняется для выплат на неё
+	ContractCredit Contract = "credit"
+
+	//карта сохраняется для платежей, инициированных клиентом
+	ContractCardOnFile Contract = "card_on_file"
+)
+
+// AdditionalData is the "additional_data" section of payment and authorization requests.
+//
+// Keys which are not described here are sent from Custom and Extra
+type AdditionalData struct {
+
+	//(необязательный) массив строк, которые будут добавлены в чек об оплате
+	ReceiptText []string `json:"receipt_text,omitempty"`
+
+	//(необязательный) массив контрактов, для которых сохраняется карта.
+	//Обязателен, если используется токен карты
+	Contract []Contract `json:"contract,omitempty"`
+
+	//(необязательный) данные браузера клиента для 3-D Secure 2.0
+	Browser *BrowserInfo `json:"browser,omitempty"`
+
+	//(необязательный) позиции чека для онлайн-кассы
+	FiscalReceipt *FiscalReceipt `json:"fiscal_receipt,omitempty"`
+
+	//(необязательный) название и версия платформы магазина, например "WooCommerce 5.1"
+	PlatformData string `json:"platform_data,omitempty"`
+
+	//(необязательный) название и версия модуля интеграции
+	IntegrationData string `json:"integration_data,omitempty"`
+
+	// Custom fields of the shop, sent as strings
+	Custom map[string]string `json:"-"`
+
+	// Extra is sent as is, use it for fields not supported by the SDK yet
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func NewAdditionalData() *AdditionalData {
+	return &AdditionalData{}
+}
+
+func (d *AdditionalData) WithReceiptText(lines ...string) *AdditionalData {
+	d.ReceiptText = append(d.ReceiptText, lines...)
+	return d
+}
+
+func (d *AdditionalData) WithContract(contracts ...Contract) *AdditionalData {
+	d.Contract = append(d.Contract, contracts...)
+	return d
+}
+
+func (d *AdditionalData) WithBrowser(browser BrowserInfo) *AdditionalData {
+	d.Browser = &browser
+	return d
+}
+
+func (d *AdditionalData) WithFiscalReceipt(receipt FiscalReceipt) *AdditionalData {
+	c := receipt.Clone()
+	d.FiscalReceipt = &c
+	return d
+}
+
+func (d *AdditionalData) WithPlatformData(platformData string) *AdditionalData {
+	d.PlatformData = platformData
+	return d
+}
+
+func (d *AdditionalData) WithIntegrationData(integrationData string) *AdditionalData {
+	d.IntegrationData = integrationData
+	return d
+}
+
+func (d *AdditionalData) WithCustom(key, value string) *AdditionalData {
+	if d.Custom == nil {
+		d.Custom = map[string]string{}
+	}
+	d.Custom[key] = value
+	return d
+}
+
+func (d *AdditionalData) WithExtra(key string, value json.RawMessage) *AdditionalData {
+	if d.Extra == nil {
+		d.Extra = map[string]json.RawMessage{}
+	}
+	d.Extra[key] = value
+	return d
+}
+
+// copyOf returns deep copy of d or new AdditionalData if d is nil. Requests copied by value share
+// *AdditionalData, so their With* methods change a copy
+func copyOf(d *AdditionalData) *AdditionalData {
+	if d == nil {
+		return NewAdditionalData()
+	}
+	c := d.Clone()
+	return &c
+}
+
+// Clone returns deep copy, so changes of the original don't affect the copy
+func (d AdditionalData) Clone() AdditionalData {
+	c := d
+
+	c.ReceiptText = append([]string(nil), d.ReceiptText...)
+	c.Contract = append([]Contract(nil), d.Contract...)
+
+	if d.Browser != nil {
+		b := *d.Browser
+		c.Browser = &b
+	}
+
+	if d.FiscalReceipt != nil {
+		r := d.FiscalReceipt.Clone()
+		c.FiscalReceipt = &r
+	}
+
+	if d.Custom != nil {
+		c.Custom = make(map[string]string, len(d.Custom))
+		for k, v := range d.Custom {
+			c.Custom[k] = v
+		}
+	}
+
+	if d.Extra != nil {
+		c.Extra = make(map[string]json.RawMessage, len(d.Extra))
+		for k, v := range d.Extra {
+			c.Extra[k] = append(json.RawMessage(nil), v...)
+		}
+	}
+
+	return c
+}
+
+// MarshalJSON writes typed fields, then Custom and Extra keys. Keys of typed fields can't be overridden, Extra overrides Custom
+func (d AdditionalData) MarshalJSON() ([]byte, error) {
+	type additionalData AdditionalData
+
+	b, err := json.Marshal(additionalData(d))
+	if err != nil || (len(d.Custom) == 0 && len(d.Extra) == 0) {
+		return b, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	for k, v := range d.Custom {
+		if _, ok := knownAdditionalDataFields[k]; !ok {
+			s, _ := json.Marshal(v)
+			fields[k] = s
+		}
+	}
+	for k, v := range d.Extra {
+		if _, ok := knownAdditionalDataFields[k]; !ok {
+			fields[k] = v
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON keeps unknown keys in Extra
+func (d *AdditionalData) UnmarshalJSON(b []byte) error {
+	type additionalData AdditionalData
+
+	if err := json.Unmarshal(b, (*additionalData)(d)); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	for k := range knownAdditionalDataFields {
+		delete(raw, k)
+	}
+
+	d.Extra = nil
+	if len(raw) > 0 {
+		d.Extra = raw
+	}
+
+	return nil
+}
+
+var knownAdditionalDataFields = jsonFields(reflect.TypeOf(AdditionalData{}))
@@ -30,15 +30,21 @@ type AuthorizationRequest struct {
 		//на который bePaid будет перенаправлять клиента после возврата с 3-D Secure проверки
 		ReturnUrl string `json:"return_url,omitempty"`
 
+		//(необязательный) язык сообщений шлюза в ответе, например ru, be, en
+		Language Language `json:"language,omitempty"`
+
 		//true или false. Транзакция будет тестовой, если значение true.
 		Test bool `json:"test"`
 
 		CreditCard CreditCard `json:"credit_card"`
 
 		//секция, содержащая дополнительную информацию о платеже
-		AdditionalData map[string]interface{} `json:"additional_data,omitempty"`
+		AdditionalData *AdditionalData `json:"additional_data,omitempty"`
 
 		Customer *Customer `json:"customer,omitempty"`
+
+		//(необязательный) секция, содержащая адрес плательщика. Используется для AVS проверки
+		BillingAddress *BillingAddress `json:"billing_address,omitempty"`
 	} `json:"request"`
 }
 
@@ -66,19 +72,55 @@ func (a *AuthorizationRequest) WithReturnUrl(returnUrl string) *AuthorizationReq
 	return a
 }
 
-// WithAdditionalData saves argument to AuthorizationRequest.Request.AdditionalData field.
-//
-// Don't change content of additionalData after function call.
-func (a *AuthorizationRequest) WithAdditionalData(additionalData map[string]interface{}) *AuthorizationRequest {
-	a.Request.AdditionalData = additionalData
+// WithAdditionalData saves copy of argument to AuthorizationRequest.Request.AdditionalData field
+func (a *AuthorizationRequest) WithAdditionalData(additionalData AdditionalData) *AuthorizationRequest {
+	c := additionalData.Clone()
+	a.Request.AdditionalData = &c
+	return a
+}
+
+// WithBrowserInfo sets Request.AdditionalData.Browser, other fields of AdditionalData are kept
+func (a *AuthorizationRequest) WithBrowserInfo(browser BrowserInfo) *AuthorizationRequest {
+	a.Request.AdditionalData = copyOf(a.Request.AdditionalData).WithBrowser(browser)
+	return a
+}
+
+// WithFiscalReceipt sets copy of receipt to Request.AdditionalData.FiscalReceipt, other fields of AdditionalData are kept
+func (a *AuthorizationRequest) WithFiscalReceipt(receipt FiscalReceipt) *AuthorizationRequest {
+	a.Request.AdditionalData = copyOf(a.Request.AdditionalData).WithFiscalReceipt(receipt)
+	return a
+}
+
+// ValidateFiscalReceipt checks fiscal receipt against Request.Amount. Request without fiscal receipt is valid
+func (a AuthorizationRequest) ValidateFiscalReceipt() error {
+	if a.Request.AdditionalData == nil || a.Request.AdditionalData.FiscalReceipt == nil {
+		return nil
+	}
+	return a.Request.AdditionalData.FiscalReceipt.Validate(a.Request.Amount)
+}
+
+func (a *AuthorizationRequest) WithLanguage(language Language) *AuthorizationRequest {
+	a.Request.Language = language
 	return a
 }
 
+// SetDefaultLanguage sets Request.Language if it is empty
+func (a *AuthorizationRequest) SetDefaultLanguage(language Language) {
+	if a.Request.Language == "" {
+		a.Request.Language = language
+	}
+}
+
 func (a *AuthorizationRequest) WithCustomer(customer Customer) *AuthorizationRequest {
 	a.Request.Customer = &customer
 	return a
 }
 
+func (a *AuthorizationRequest) WithBillingAddress(billingAddress BillingAddress) *AuthorizationRequest {
+	a.Request.BillingAddress = &billingAddress
+	return a
+}
+
 func (a *AuthorizationRequest) SetTest(test bool) {
 	a.Request.Test = test
 }
@@ -0,0 +1,103 @@
+package vo
+
+// Balance describes how much of an authorization or a payment is already captured, voided or refunded.
+//
+// Use NewBalance to build it from the parent transaction and its children
+type Balance struct {
+	//UID транзакции авторизации или оплаты
+	Uid string
+
+	//тип родительской транзакции: authorization или payment
+	Type string
+
+	//статус родительской транзакции
+	Status string
+
+	Currency string
+
+	//сумма авторизации или оплаты в минимальных денежных единицах
+	Amount int64
+
+	//сумма успешных списаний (capture)
+	Captured int64
+
+	//сумма успешных отмен авторизации (void)
+	Voided int64
+
+	//сумма успешных возвратов (refund), в том числе по списаниям авторизации
+	Refunded int64
+}
+
+// NewBalance sums up successful children of parent.
+//
+// transactions may contain any transactions with the same tracking_id,
+// only captures and voids of parent and refunds of parent or its captures are counted
+func NewBalance(parent Transaction, transactions []Transaction) Balance {
+	b := Balance{
+		Uid:      parent.Uid,
+		Type:     parent.Type,
+		Status:   parent.Status,
+		Currency: parent.Currency,
+		Amount:   parent.Amount,
+	}
+
+	captures := map[string]struct{}{}
+	for _, t := range transactions {
+		if t.ParentUid == parent.Uid && t.Type == capture && t.Status == success {
+			captures[t.Uid] = struct{}{}
+			b.Captured += t.Amount
+		}
+	}
+
+	for _, t := range transactions {
+		if t.Status != success {
+			continue
+		}
+
+		_, ofCapture := captures[t.ParentUid]
+		if t.ParentUid != parent.Uid && !ofCapture {
+			continue
+		}
+
+		switch t.Type {
+		case void:
+			if !ofCapture {
+				b.Voided += t.Amount
+			}
+		case refund:
+			b.Refunded += t.Amount
+		}
+	}
+
+	return b
+}
+
+// Capturable returns amount that still can be captured or voided
+func (b Balance) Capturable() int64 {
+	if b.Type != authorization || b.Status != success {
+		return 0
+	}
+	return nonNegative(b.Amount - b.Captured - b.Voided)
+}
+
+// Refundable returns amount that still can be refunded
+func (b Balance) Refundable() int64 {
+	if b.Status != success {
+		return 0
+	}
+
+	switch b.Type {
+	case payment:
+		return nonNegative(b.Amount - b.Refunded)
+	case authorization:
+		return nonNegative(b.Captured - b.Refunded)
+	}
+	return 0
+}
+
+func nonNegative(amount int64) int64 {
+	if amount < 0 {
+		return 0
+	}
+	return amount
+}
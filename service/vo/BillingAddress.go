@@ -0,0 +1,139 @@
+package vo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrBillingAddressCountry = errors.New("billing address: country must be ISO 3166-1 alpha-2 code")
+	ErrBillingAddressState   = errors.New("billing address: state must be 2-letter code of USA or Canada state")
+)
+
+// BillingAddress
+//
+// Used by bePaid for AVS (address verification) and fraud scoring
+type BillingAddress struct {
+
+	//имя владельца карты. Максимальная длина: 30 символов
+	FirstName string `json:"first_name"`
+
+	//фамилия владельца карты. Максимальная длина: 30 символов
+	LastName string `json:"last_name"`
+
+	//адрес владельца карты. Максимальная длина: 255 символов
+	Address string `json:"address"`
+
+	//страна владельца карты. 2-буквенный код страны в формате ISO 3166-1 Alpha-2, например BY
+	Country string `json:"country"`
+
+	//город владельца карты. Максимальная длина: 60 символов
+	City string `json:"city"`
+
+	//почтовый индекс владельца карты. Для country=US формат индекса - 99999 или 99999-9999
+	Zip string `json:"zip"`
+
+	//(необязательный) 2-буквенный код штата, если country=US или country=CA
+	State string `json:"state,omitempty"`
+
+	//(необязательный) телефон владельца карты. Максимальная длина: 100 символов
+	Phone string `json:"phone,omitempty"`
+
+	//поля ответа шлюза, не описанные выше. Не отправляются
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// NewBillingAddress creates BillingAddress with mandatory fields
+func NewBillingAddress(firstName, lastName, address, country, city, zip string) *BillingAddress {
+	return &BillingAddress{
+		FirstName: firstName,
+		LastName:  lastName,
+		Address:   address,
+		Country:   country,
+		City:      city,
+		Zip:       zip,
+	}
+}
+
+func (b *BillingAddress) WithState(state string) *BillingAddress {
+	b.State = state
+	return b
+}
+
+func (b *BillingAddress) WithPhone(phone string) *BillingAddress {
+	b.Phone = phone
+	return b
+}
+
+// Validate checks Country and State codes.
+//
+// State is mandatory for USA and Canada and must be empty for other countries
+func (b BillingAddress) Validate() error {
+	if _, ok := countryCodes[b.Country]; !ok {
+		return fmt.Errorf("%w: %q", ErrBillingAddressCountry, b.Country)
+	}
+
+	states, ok := stateCodes[b.Country]
+	if !ok {
+		if b.State != "" {
+			return fmt.Errorf("%w: %q for country %s", ErrBillingAddressState, b.State, b.Country)
+		}
+		return nil
+	}
+
+	if _, ok := states[b.State]; !ok {
+		return fmt.Errorf("%w: %q for country %s", ErrBillingAddressState, b.State, b.Country)
+	}
+
+	return nil
+}
+
+type codeSet map[string]struct{}
+
+func newCodeSet(codes ...string) codeSet {
+	s := make(codeSet, len(codes))
+	for _, c := range codes {
+		s[c] = struct{}{}
+	}
+	return s
+}
+
+// ISO 3166-1 alpha-2
+var countryCodes = newCodeSet(
+	"AD", "AE", "AF", "AG", "AI", "AL", "AM", "AO", "AQ", "AR", "AS", "AT", "AU", "AW", "AX", "AZ",
+	"BA", "BB", "BD", "BE", "BF", "BG", "BH", "BI", "BJ", "BL", "BM", "BN", "BO", "BQ", "BR", "BS",
+	"BT", "BV", "BW", "BY", "BZ", "CA", "CC", "CD", "CF", "CG", "CH", "CI", "CK", "CL", "CM", "CN",
+	"CO", "CR", "CU", "CV", "CW", "CX", "CY", "CZ", "DE", "DJ", "DK", "DM", "DO", "DZ", "EC", "EE",
+	"EG", "EH", "ER", "ES", "ET", "FI", "FJ", "FK", "FM", "FO", "FR", "GA", "GB", "GD", "GE", "GF",
+	"GG", "GH", "GI", "GL", "GM", "GN", "GP", "GQ", "GR", "GS", "GT", "GU", "GW", "GY", "HK", "HM",
+	"HN", "HR", "HT", "HU", "ID", "IE", "IL", "IM", "IN", "IO", "IQ", "IR", "IS", "IT", "JE", "JM",
+	"JO", "JP", "KE", "KG", "KH", "KI", "KM", "KN", "KP", "KR", "KW", "KY", "KZ", "LA", "LB", "LC",
+	"LI", "LK", "LR", "LS", "LT", "LU", "LV", "LY", "MA", "MC", "MD", "ME", "MF", "MG", "MH", "MK",
+	"ML", "MM", "MN", "MO", "MP", "MQ", "MR", "MS", "MT", "MU", "MV", "MW", "MX", "MY", "MZ", "NA",
+	"NC", "NE", "NF", "NG", "NI", "NL", "NO", "NP", "NR", "NU", "NZ", "OM", "PA", "PE", "PF", "PG",
+	"PH", "PK", "PL", "PM", "PN", "PR", "PS", "PT", "PW", "PY", "QA", "RE", "RO", "RS", "RU", "RW",
+	"SA", "SB", "SC", "SD", "SE", "SG", "SH", "SI", "SJ", "SK", "SL", "SM", "SN", "SO", "SR", "SS",
+	"ST", "SV", "SX", "SY", "SZ", "TC", "TD", "TF", "TG", "TH", "TJ", "TK", "TL", "TM", "TN", "TO",
+	"TR", "TT", "TV", "TW", "TZ", "UA", "UG", "UM", "US", "UY", "UZ", "VA", "VC", "VE", "VG", "VI",
+	"VN", "VU", "WF", "WS", "YE", "YT", "ZA", "ZM", "ZW",
+)
+
+// countries for which bePaid requires state
+var stateCodes = map[string]codeSet{
+	"US": newCodeSet(
+		"AL", "AK", "AZ", "AR", "CA", "CO", "CT", "DE", "DC", "FL", "GA", "HI", "ID", "IL", "IN", "IA",
+		"KS", "KY", "LA", "ME", "MD", "MA", "MI", "MN", "MS", "MO", "MT", "NE", "NV", "NH", "NJ", "NM",
+		"NY", "NC", "ND", "OH", "OK", "OR", "PA", "RI", "SC", "SD", "TN", "TX", "UT", "VT", "VA", "WA",
+		"WV", "WI", "WY", "AS", "GU", "MP", "PR", "VI", "UM", "AA", "AE", "AP",
+	),
+	"CA": newCodeSet(
+		"AB", "BC", "MB", "NB", "NL", "NS", "NT", "NU", "ON", "PE", "QC", "SK", "YT",
+	),
+}
+
+func (b *BillingAddress) UnmarshalJSON(data []byte) (err error) {
+	type billingAddress BillingAddress
+	b.Extra, err = unmarshalExtra(data, (*billingAddress)(b))
+	return err
+}
@@ -0,0 +1,120 @@
+package vo
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// BrowserInfo is the "browser" section of AdditionalData, required by 3-D Secure 2.0 frictionless flow
+type BrowserInfo struct {
+
+	//значение HTTP заголовка Accept браузера клиента
+	AcceptHeader string `json:"accept_header"`
+
+	//значение HTTP заголовка User-Agent браузера клиента
+	UserAgent string `json:"user_agent"`
+
+	//язык браузера в формате IETF BCP 47, например ru-RU
+	Language string `json:"language"`
+
+	//ширина и высота экрана в пикселях
+	ScreenWidth  int `json:"screen_width"`
+	ScreenHeight int `json:"screen_height"`
+
+	//глубина цвета экрана в битах: 1, 4, 8, 15, 16, 24, 32 или 48
+	ScreenColorDepth int `json:"screen_color_depth"`
+
+	//ширина и высота окна браузера в пикселях
+	WindowWidth  int `json:"window_width"`
+	WindowHeight int `json:"window_height"`
+
+	//разница между UTC и локальным временем клиента в минутах, как возвращает Date.getTimezoneOffset()
+	TimeZone int `json:"time_zone"`
+
+	//(необязательный) название часового пояса, например Europe/Minsk
+	TimeZoneName string `json:"time_zone_name,omitempty"`
+
+	JavaEnabled       bool `json:"java_enabled"`
+	JavascriptEnabled bool `json:"javascript_enabled"`
+}
+
+// BrowserHints are collected by javascript on the checkout page, e.g.
+//
+//	screen.width, screen.height, screen.colorDepth, window.innerWidth, window.innerHeight,
+//	new Date().getTimezoneOffset(), Intl.DateTimeFormat().resolvedOptions().timeZone,
+//	navigator.language, navigator.javaEnabled()
+//
+// Zero BrowserHints mean javascript is disabled
+type BrowserHints struct {
+	ScreenWidth      int    `json:"screen_width"`
+	ScreenHeight     int    `json:"screen_height"`
+	ScreenColorDepth int    `json:"screen_color_depth"`
+	WindowWidth      int    `json:"window_width"`
+	WindowHeight     int    `json:"window_height"`
+	TimeZone         int    `json:"time_zone"`
+	TimeZoneName     string `json:"time_zone_name"`
+	Language         string `json:"language"`
+	JavaEnabled      bool   `json:"java_enabled"`
+}
+
+// BrowserHintsFromForm reads hints from form fields named as json tags of BrowserHints, e.g. hidden inputs of checkout form.
+// Missing or invalid numbers are left zero
+func BrowserHintsFromForm(form url.Values) BrowserHints {
+	atoi := func(key string) int {
+		n, _ := strconv.Atoi(form.Get(key))
+		return n
+	}
+
+	java, _ := strconv.ParseBool(form.Get("java_enabled"))
+
+	return BrowserHints{
+		ScreenWidth:      atoi("screen_width"),
+		ScreenHeight:     atoi("screen_height"),
+		ScreenColorDepth: atoi("screen_color_depth"),
+		WindowWidth:      atoi("window_width"),
+		WindowHeight:     atoi("window_height"),
+		TimeZone:         atoi("time_zone"),
+		TimeZoneName:     form.Get("time_zone_name"),
+		Language:         form.Get("language"),
+		JavaEnabled:      java,
+	}
+}
+
+// NewBrowserInfo takes headers from request of the client's browser and the rest from hints.
+// If hints have no language, the first language of Accept-Language header is used
+func NewBrowserInfo(r *http.Request, hints BrowserHints) BrowserInfo {
+	b := BrowserInfo{
+		AcceptHeader:      r.Header.Get("Accept"),
+		UserAgent:         r.Header.Get("User-Agent"),
+		Language:          hints.Language,
+		ScreenWidth:       hints.ScreenWidth,
+		ScreenHeight:      hints.ScreenHeight,
+		ScreenColorDepth:  hints.ScreenColorDepth,
+		WindowWidth:       hints.WindowWidth,
+		WindowHeight:      hints.WindowHeight,
+		TimeZone:          hints.TimeZone,
+		TimeZoneName:      hints.TimeZoneName,
+		JavaEnabled:       hints.JavaEnabled,
+		JavascriptEnabled: hints != BrowserHints{},
+	}
+
+	if b.Language == "" {
+		b.Language = acceptLanguage(r.Header.Get("Accept-Language"))
+	}
+
+	return b
+}
+
+// acceptLanguage returns the first language of Accept-Language header, "ru-RU,ru;q=0.9" gives "ru-RU"
+func acceptLanguage(header string) string {
+	lang := strings.Split(header, ",")[0]
+	lang = strings.Split(lang, ";")[0]
+	lang = strings.TrimSpace(lang)
+
+	if lang == "*" {
+		return ""
+	}
+	return lang
+}
@@ -1,14 +1,64 @@
 package vo
 
+import "encoding/json"
+
 type CaptureRequest struct {
-	Amount    int    `json:"amount"`
-	ParentUid string `json:"parent_uid"`
-	Status    string `json:"status"`
-	Message   string `json:"message"`
-	Uid       string `json:"uid"`
-	GatewayId int    `json:"gateway_id"`
+	Request struct {
+
+		//UID транзакции авторизации
+		ParentUid string `json:"parent_uid"`
+
+		//сумма списания в минимальных денежных единицах, например 1000 для $10.00
+		Amount int64 `json:"amount"`
+
+		//(необязательный) true или false. Параметр управляет процессом проверки входящего запроса на уникальность.
+		//Если в течение 30 секунд придет запрос на списание средств с одинаковыми amount и parent_uid, то запрос будет отклонен.
+		//По умолчанию, этот параметр имеет значение true
+		DuplicateCheck *bool `json:"duplicate_check,omitempty"`
+	} `json:"request"`
+
+	// Deprecated: use Request.Amount. Sent only if Request.ParentUid is empty
+	Amount int `json:"-"`
+
+	// Deprecated: use Request.ParentUid. Sent only if Request.ParentUid is empty
+	ParentUid string `json:"-"`
+
+	// Deprecated: fields of the response, they are never sent. Use TransactionResponse
+	Status    string `json:"-"`
+	Message   string `json:"-"`
+	Uid       string `json:"-"`
+	GatewayId int    `json:"-"`
 }
 
+// NewCaptureRequest creates CaptureRequest with mandatory fields
+//
+// Argument order and int amount are kept for compatibility with previous flat CaptureRequest,
+// amounts above int range are set to Request.Amount
 func NewCaptureRequest(amount int, parentUid string) *CaptureRequest {
-	return &CaptureRequest{Amount: amount, ParentUid: parentUid}
+	r := &CaptureRequest{}
+
+	r.Request.ParentUid = parentUid
+	r.Request.Amount = int64(amount)
+
+	return r
+}
+
+func (cr *CaptureRequest) WithDuplicateCheck(duplicateCheck bool) *CaptureRequest {
+	cr.Request.DuplicateCheck = &duplicateCheck
+	return cr
+}
+
+// MarshalJSON sends deprecated flat Amount and ParentUid of CaptureRequest{Amount: 50, ParentUid: uid}
+// in the request envelope
+func (cr CaptureRequest) MarshalJSON() ([]byte, error) {
+	type envelope struct {
+		Request interface{} `json:"request"`
+	}
+
+	request := cr.Request
+	if request.ParentUid == "" {
+		request.ParentUid = cr.ParentUid
+		request.Amount = int64(cr.Amount)
+	}
+	return json.Marshal(envelope{Request: request})
 }
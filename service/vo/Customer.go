@@ -1,5 +1,7 @@
 package vo
 
+import "encoding/json"
+
 type Customer struct {
 
 	//IP-адрес клиента, производящего оплату в вашем магазине
@@ -14,6 +16,9 @@ type Customer struct {
 
 	//(необязательный) дата рождения клиента в формате ISO 8601 YYYY-MM-DD
 	BirthDate string `json:"birth_date"`
+
+	//поля ответа шлюза, не описанные выше. Не отправляются
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 func NewCustomer(ip, email string) *Customer {
@@ -29,3 +34,9 @@ func (c *Customer) WithBirthDate(birthDate string) *Customer {
 	c.BirthDate = birthDate
 	return c
 }
+
+func (c *Customer) UnmarshalJSON(b []byte) (err error) {
+	type customer Customer
+	c.Extra, err = unmarshalExtra(b, (*customer)(c))
+	return err
+}
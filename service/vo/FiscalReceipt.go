@@ -0,0 +1,217 @@
+package vo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrFiscalReceipt = errors.New("fiscal receipt is invalid")
+
+// FiscalReceiptError lists all problems of fiscal receipt. errors.Is matches ErrFiscalReceipt
+type FiscalReceiptError struct {
+	Problems []string
+}
+
+func (e *FiscalReceiptError) Error() string {
+	return "fiscal receipt: " + strings.Join(e.Problems, "; ")
+}
+
+func (e *FiscalReceiptError) Is(target error) bool {
+	return target == ErrFiscalReceipt
+}
+
+// TaxRate is VAT rate of fiscal receipt item
+type TaxRate string
+
+const (
+	//без НДС
+	TaxNone TaxRate = "none"
+
+	//НДС 0%
+	TaxVat0 TaxRate = "vat0"
+
+	//НДС 10%
+	TaxVat10 TaxRate = "vat10"
+
+	//НДС 20%
+	TaxVat20 TaxRate = "vat20"
+
+	//НДС по расчётной ставке 10/110
+	TaxVat110 TaxRate = "vat110"
+
+	//НДС по расчётной ставке 20/120
+	TaxVat120 TaxRate = "vat120"
+)
+
+// PaymentSubject is the kind of fiscal receipt item
+type PaymentSubject string
+
+const (
+	//товар
+	SubjectCommodity PaymentSubject = "commodity"
+
+	//подакцизный товар
+	SubjectExcise PaymentSubject = "excise"
+
+	//работа
+	SubjectJob PaymentSubject = "job"
+
+	//услуга
+	SubjectService PaymentSubject = "service"
+
+	//платёж, например погашение кредита
+	SubjectPayment PaymentSubject = "payment"
+
+	//иной предмет расчёта
+	SubjectAnother PaymentSubject = "another"
+)
+
+// maxItemNameLength of online cash registers
+const maxItemNameLength = 128
+
+// FiscalItem is a line of fiscal receipt
+type FiscalItem struct {
+
+	//наименование товара или услуги. Максимальная длина: 128 символов
+	Name string `json:"name"`
+
+	//количество, может быть дробным для весовых товаров
+	Quantity float64 `json:"quantity"`
+
+	//цена за единицу в минимальных денежных единицах
+	Price int64 `json:"price"`
+
+	//стоимость позиции в минимальных денежных единицах, Price * Quantity с округлением
+	Amount int64 `json:"amount"`
+
+	//ставка НДС
+	Tax TaxRate `json:"tax"`
+
+	//предмет расчёта
+	PaymentSubject PaymentSubject `json:"payment_subject"`
+}
+
+// NewFiscalItem creates commodity item, Amount is calculated from price and quantity
+func NewFiscalItem(name string, quantity float64, price int64, tax TaxRate) *FiscalItem {
+	return &FiscalItem{
+		Name:           name,
+		Quantity:       quantity,
+		Price:          price,
+		Amount:         itemAmount(price, quantity),
+		Tax:            tax,
+		PaymentSubject: SubjectCommodity,
+	}
+}
+
+func (i *FiscalItem) WithPaymentSubject(paymentSubject PaymentSubject) *FiscalItem {
+	i.PaymentSubject = paymentSubject
+	return i
+}
+
+// WithAmount overrides calculated amount, e.g. to apply a discount to the item
+func (i *FiscalItem) WithAmount(amount int64) *FiscalItem {
+	i.Amount = amount
+	return i
+}
+
+func itemAmount(price int64, quantity float64) int64 {
+	return int64(math.Round(float64(price) * quantity))
+}
+
+// FiscalReceipt is the data of online cash register receipt sent in additional_data of payment and authorization
+type FiscalReceipt struct {
+	Items []FiscalItem `json:"items"`
+
+	//(необязательный) email покупателя для отправки электронного чека
+	Email string `json:"email,omitempty"`
+
+	//(необязательный) телефон покупателя для отправки электронного чека
+	Phone string `json:"phone,omitempty"`
+}
+
+func NewFiscalReceipt(items ...FiscalItem) *FiscalReceipt {
+	return &FiscalReceipt{Items: items}
+}
+
+func (r *FiscalReceipt) WithItem(item FiscalItem) *FiscalReceipt {
+	r.Items = append(r.Items, item)
+	return r
+}
+
+func (r *FiscalReceipt) WithEmail(email string) *FiscalReceipt {
+	r.Email = email
+	return r
+}
+
+func (r *FiscalReceipt) WithPhone(phone string) *FiscalReceipt {
+	r.Phone = phone
+	return r
+}
+
+// Total is the sum of item amounts
+func (r FiscalReceipt) Total() int64 {
+	var total int64
+	for _, i := range r.Items {
+		total += i.Amount
+	}
+	return total
+}
+
+// Validate checks items and that their total equals amount of the request in minimal currency units.
+//
+// Item amount may be less than price * quantity because of discount, but never more
+func (r FiscalReceipt) Validate(amount int64) error {
+	var problems []string
+
+	if len(r.Items) == 0 {
+		problems = append(problems, "no items")
+	}
+
+	for n, i := range r.Items {
+		prefix := fmt.Sprintf("items[%d]", n)
+
+		if strings.TrimSpace(i.Name) == "" {
+			problems = append(problems, prefix+": name is required")
+		} else if utf8.RuneCountInString(i.Name) > maxItemNameLength {
+			problems = append(problems, fmt.Sprintf("%s: name is longer than %d characters", prefix, maxItemNameLength))
+		}
+		if i.Quantity <= 0 {
+			problems = append(problems, prefix+": quantity must be positive")
+		}
+		if i.Price < 0 {
+			problems = append(problems, prefix+": price must not be negative")
+		}
+		if i.Amount < 0 || i.Amount > itemAmount(i.Price, i.Quantity) {
+			problems = append(problems, fmt.Sprintf("%s: amount %d must be between 0 and price * quantity %d",
+				prefix, i.Amount, itemAmount(i.Price, i.Quantity)))
+		}
+		switch i.Tax {
+		case TaxNone, TaxVat0, TaxVat10, TaxVat20, TaxVat110, TaxVat120:
+		default:
+			problems = append(problems, fmt.Sprintf("%s: unknown tax %q", prefix, i.Tax))
+		}
+		switch i.PaymentSubject {
+		case SubjectCommodity, SubjectExcise, SubjectJob, SubjectService, SubjectPayment, SubjectAnother:
+		default:
+			problems = append(problems, fmt.Sprintf("%s: unknown payment subject %q", prefix, i.PaymentSubject))
+		}
+	}
+
+	if total := r.Total(); len(r.Items) > 0 && total != amount {
+		problems = append(problems, fmt.Sprintf("total of items %d doesn't match amount %d", total, amount))
+	}
+
+	if len(problems) > 0 {
+		return &FiscalReceiptError{Problems: problems}
+	}
+	return nil
+}
+
+// Clone returns deep copy
+func (r FiscalReceipt) Clone() FiscalReceipt {
+	r.Items = append([]FiscalItem(nil), r.Items...)
+	return r
+}
@@ -0,0 +1,10 @@
+package vo
+
+// Language of gateway messages and payment pages, ISO 639-1 code
+type Language string
+
+const (
+	LanguageRussian    Language = "ru"
+	LanguageBelarusian Language = "be"
+	LanguageEnglish    Language = "en"
+)
@@ -41,15 +41,21 @@ type PaymentRequest struct {
 		//bePaid будет перенаправлять клиента после возврата с 3-D Secure проверки
 		ReturnUrl string `json:"return_url,omitempty"`
 
+		//(необязательный) язык сообщений шлюза в ответе, например ru, be, en
+		Language Language `json:"language,omitempty"`
+
 		//true или false. Транзакция будет тестовой, если значение true.
 		Test bool `json:"test"`
 
 		CreditCard CreditCard `json:"credit_card"`
 
 		//секция, содержащая дополнительную информацию о платеже
-		AdditionalData map[string]interface{} `json:"additional_data,omitempty"`
+		AdditionalData *AdditionalData `json:"additional_data,omitempty"`
 
 		Customer *Customer `json:"customer,omitempty"`
+
+		//(необязательный) секция, содержащая адрес плательщика. Используется для AVS проверки
+		BillingAddress *BillingAddress `json:"billing_address,omitempty"`
 	} `json:"request"`
 }
 
@@ -82,15 +88,55 @@ func (a *PaymentRequest) WithReturnUrl(returnUrl string) *PaymentRequest {
 	return a
 }
 
-// WithAdditionalData saves argument to AuthorizationRequest.Request.AdditionalData field.
-//
-// Don't change content of additionalData after function call.
-func (a *PaymentRequest) WithAdditionalData(additionalData map[string]interface{}) *PaymentRequest {
-	a.Request.AdditionalData = additionalData
+// WithAdditionalData saves copy of argument to PaymentRequest.Request.AdditionalData field
+func (a *PaymentRequest) WithAdditionalData(additionalData AdditionalData) *PaymentRequest {
+	c := additionalData.Clone()
+	a.Request.AdditionalData = &c
+	return a
+}
+
+// WithBrowserInfo sets Request.AdditionalData.Browser, other fields of AdditionalData are kept
+func (a *PaymentRequest) WithBrowserInfo(browser BrowserInfo) *PaymentRequest {
+	a.Request.AdditionalData = copyOf(a.Request.AdditionalData).WithBrowser(browser)
+	return a
+}
+
+// WithFiscalReceipt sets copy of receipt to Request.AdditionalData.FiscalReceipt, other fields of AdditionalData are kept
+func (a *PaymentRequest) WithFiscalReceipt(receipt FiscalReceipt) *PaymentRequest {
+	a.Request.AdditionalData = copyOf(a.Request.AdditionalData).WithFiscalReceipt(receipt)
+	return a
+}
+
+// ValidateFiscalReceipt checks fiscal receipt against Request.Amount. Request without fiscal receipt is valid
+func (a PaymentRequest) ValidateFiscalReceipt() error {
+	if a.Request.AdditionalData == nil || a.Request.AdditionalData.FiscalReceipt == nil {
+		return nil
+	}
+	return a.Request.AdditionalData.FiscalReceipt.Validate(a.Request.Amount)
+}
+
+func (a *PaymentRequest) WithLanguage(language Language) *PaymentRequest {
+	a.Request.Language = language
 	return a
 }
 
+// SetDefaultLanguage sets Request.Language if it is empty
+func (a *PaymentRequest) SetDefaultLanguage(language Language) {
+	if a.Request.Language == "" {
+		a.Request.Language = language
+	}
+}
+
 func (a *PaymentRequest) WithCustomer(customer Customer) *PaymentRequest {
 	a.Request.Customer = &customer
 	return a
 }
+
+func (a *PaymentRequest) WithBillingAddress(billingAddress BillingAddress) *PaymentRequest {
+	a.Request.BillingAddress = &billingAddress
+	return a
+}
+
+func (a *PaymentRequest) SetTest(test bool) {
+	a.Request.Test = test
+}
@@ -0,0 +1,137 @@
+package vo
+
+// State is the lifecycle state of a payment or an authorization:
+//
+//	incomplete -> successful (authorized or captured) | failed | expired
+//	authorized -> captured -> partially_refunded -> refunded
+//	authorized -> voided
+type State string
+
+const (
+	StateUnknown           State = ""
+	StateIncomplete        State = "incomplete"
+	StateFailed            State = "failed"
+	StateExpired           State = "expired"
+	StateAuthorized        State = "authorized"
+	StateVoided            State = "voided"
+	StateCaptured          State = "captured"
+	StatePartiallyRefunded State = "partially_refunded"
+	StateRefunded          State = "refunded"
+)
+
+// Operation is a gateway operation on existing transaction
+type Operation string
+
+const (
+	OperationCapture Operation = "capture"
+	OperationVoid    Operation = "void"
+	OperationRefund  Operation = "refund"
+)
+
+var allowedOperations = map[State][]Operation{
+	StateAuthorized:        {OperationCapture, OperationVoid},
+	StateCaptured:          {OperationRefund},
+	StatePartiallyRefunded: {OperationRefund},
+}
+
+// State returns state of the transaction judging only by its own type and status.
+//
+// Authorization stays StateAuthorized after capture or void,
+// use Balance.State if children of transaction should be taken into account
+func (tr *TransactionResponse) State() State {
+	switch tr.Transaction.Status {
+	case incomplete:
+		return StateIncomplete
+	case failed:
+		return StateFailed
+	case expired:
+		return StateExpired
+	case success:
+	default:
+		return StateUnknown
+	}
+
+	switch tr.Transaction.Type {
+	case authorization:
+		return StateAuthorized
+	case payment, capture:
+		return StateCaptured
+	case void:
+		return StateVoided
+	case refund:
+		return StateRefunded
+	}
+	return StateUnknown
+}
+
+// AllowedOperations returns operations which may be requested with transaction uid as parent_uid
+func (tr *TransactionResponse) AllowedOperations() []Operation {
+	return tr.State().AllowedOperations()
+}
+
+// AllowedOperations returns operations allowed in state s
+func (s State) AllowedOperations() []Operation {
+	return allowedOperations[s]
+}
+
+// Allows reports whether operation is allowed in state s
+func (s State) Allows(operation Operation) bool {
+	for _, o := range allowedOperations[s] {
+		if o == operation {
+			return true
+		}
+	}
+	return false
+}
+
+// State returns state of the parent transaction taking captures, voids and refunds into account
+func (b Balance) State() State {
+	switch b.Status {
+	case incomplete:
+		return StateIncomplete
+	case failed:
+		return StateFailed
+	case expired:
+		return StateExpired
+	case success:
+	default:
+		return StateUnknown
+	}
+
+	captured := b.Amount
+	switch b.Type {
+	case authorization:
+		if b.Captured == 0 {
+			if b.Voided > 0 {
+				return StateVoided
+			}
+			return StateAuthorized
+		}
+		captured = b.Captured
+	case payment:
+	default:
+		return StateUnknown
+	}
+
+	switch {
+	case b.Refunded == 0:
+		return StateCaptured
+	case b.Refunded < captured:
+		return StatePartiallyRefunded
+	}
+	return StateRefunded
+}
+
+// Allows reports whether operation is allowed for the parent transaction.
+//
+// Unlike State.Allows, it also checks remaining amounts,
+// so partially captured authorization still allows capture and void
+func (b Balance) Allows(operation Operation) bool {
+	switch operation {
+	case OperationCapture, OperationVoid:
+		return b.Capturable() > 0
+	case OperationRefund:
+		return b.Refundable() > 0
+	}
+	return false
+}
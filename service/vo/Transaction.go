@@ -0,0 +1,231 @@
+package vo
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Transaction is the "transaction" section of gateway response.
+//
+// Fields unknown to the SDK are kept in Extra
+type Transaction struct {
+	Uid        string `json:"uid"`
+	ParentUid  string `json:"parent_uid"`
+	TrackingId string `json:"tracking_id"`
+
+	//тип транзакции: payment, authorization, capture, void, refund, ...
+	Type string `json:"type"`
+
+	//статус транзакции: successful, failed, incomplete, expired
+	Status string `json:"status"`
+
+	Message            string `json:"message"`
+	MessageTransaction string `json:"message_transaction"`
+
+	//сумма в минимальных денежных единицах
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+
+	Description       string `json:"description"`
+	Language          string `json:"language"`
+	PaymentMethodType string `json:"payment_method_type"`
+	RefId             string `json:"ref_id"`
+	GatewayId         int    `json:"gateway_id"`
+	ReceiptUrl        string `json:"receipt_url"`
+	RedirectUrl       string `json:"redirect_url"`
+	Test              bool   `json:"test"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	PaidAt     *time.Time `json:"paid_at,omitempty"`
+	ExpiredAt  *time.Time `json:"expired_at,omitempty"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty"`
+	SettledAt  *time.Time `json:"settled_at,omitempty"`
+	ReversedAt *time.Time `json:"reversed_at,omitempty"`
+
+	Customer       *Customer       `json:"customer,omitempty"`
+	CreditCard     *CreditCardInfo `json:"credit_card,omitempty"`
+	BillingAddress *BillingAddress `json:"billing_address,omitempty"`
+
+	//результат операции в банке-эквайере, заполняется секция соответствующая типу транзакции
+	Payment       *ProcessingResult `json:"payment,omitempty"`
+	Authorization *ProcessingResult `json:"authorization,omitempty"`
+	Capture       *ProcessingResult `json:"capture,omitempty"`
+	Void          *ProcessingResult `json:"void,omitempty"`
+	Refund        *ProcessingResult `json:"refund,omitempty"`
+
+	ThreeDSecureVerification *ThreeDSecureVerification `json:"three_d_secure_verification,omitempty"`
+	AvsCvcVerification       *AvsCvcVerification       `json:"avs_cvc_verification,omitempty"`
+
+	AdditionalData map[string]interface{} `json:"additional_data,omitempty"`
+
+	//поля ответа, не описанные выше
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// CreditCardInfo is the "credit_card" section of gateway response.
+//
+// Unlike CreditCard it never contains full number and verification value
+type CreditCardInfo struct {
+	Holder string `json:"holder"`
+
+	//хэш номера карты
+	Stamp string `json:"stamp"`
+
+	//токен карты, который можно использовать в следующих оплатах
+	Token         string `json:"token"`
+	TokenProvider string `json:"token_provider"`
+
+	//visa, master, belkart, ...
+	Brand         string `json:"brand"`
+	Product       string `json:"product"`
+	First1        string `json:"first_1"`
+	Last4         string `json:"last_4"`
+	Bin           string `json:"bin"`
+	IssuerCountry string `json:"issuer_country"`
+	IssuerName    string `json:"issuer_name"`
+	ExpMonth      int    `json:"exp_month"`
+	ExpYear       int    `json:"exp_year"`
+
+	//поля ответа, не описанные выше
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// ProcessingResult is the operation section of gateway response (payment, authorization, capture, void or refund)
+type ProcessingResult struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+
+	//код авторизации
+	AuthCode string `json:"auth_code"`
+
+	//код ответа банка-эквайера
+	BankCode string `json:"bank_code"`
+
+	//retrieval reference number
+	Rrn   string `json:"rrn"`
+	RefId string `json:"ref_id"`
+
+	GatewayId         int    `json:"gateway_id"`
+	BillingDescriptor string `json:"billing_descriptor"`
+	Amount            int64  `json:"amount"`
+	Currency          string `json:"currency"`
+
+	//поля ответа, не описанные выше
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type ThreeDSecureVerification struct {
+	Status        string `json:"status"`
+	Message       string `json:"message"`
+	VeStatus      string `json:"ve_status"`
+	PaStatus      string `json:"pa_status"`
+	AcsUrl        string `json:"acs_url"`
+	Eci           string `json:"eci"`
+	Xid           string `json:"xid"`
+	Cavv          string `json:"cavv"`
+	CavvAlgorithm string `json:"cavv_algorithm"`
+	FailReason    string `json:"fail_reason"`
+
+	//поля ответа, не описанные выше
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type AvsCvcVerification struct {
+	AvsVerification VerificationResult `json:"avs_verification"`
+	CvcVerification VerificationResult `json:"cvc_verification"`
+
+	//поля ответа, не описанные выше
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type VerificationResult struct {
+	ResultCode string `json:"result_code"`
+
+	//поля ответа, не описанные выше
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (t *Transaction) UnmarshalJSON(b []byte) (err error) {
+	type transaction Transaction
+	t.Extra, err = unmarshalExtra(b, (*transaction)(t))
+	return err
+}
+
+func (c *CreditCardInfo) UnmarshalJSON(b []byte) (err error) {
+	type creditCardInfo CreditCardInfo
+	c.Extra, err = unmarshalExtra(b, (*creditCardInfo)(c))
+	return err
+}
+
+func (r *ProcessingResult) UnmarshalJSON(b []byte) (err error) {
+	type processingResult ProcessingResult
+	r.Extra, err = unmarshalExtra(b, (*processingResult)(r))
+	return err
+}
+
+func (v *ThreeDSecureVerification) UnmarshalJSON(b []byte) (err error) {
+	type threeDSecureVerification ThreeDSecureVerification
+	v.Extra, err = unmarshalExtra(b, (*threeDSecureVerification)(v))
+	return err
+}
+
+func (v *AvsCvcVerification) UnmarshalJSON(b []byte) (err error) {
+	type avsCvcVerification AvsCvcVerification
+	v.Extra, err = unmarshalExtra(b, (*avsCvcVerification)(v))
+	return err
+}
+
+func (r *VerificationResult) UnmarshalJSON(b []byte) (err error) {
+	type verificationResult VerificationResult
+	r.Extra, err = unmarshalExtra(b, (*verificationResult)(r))
+	return err
+}
+
+// unmarshalExtra decodes b to v and returns keys of b which are not fields of v, nil if there are none.
+// v must be a pointer to a struct without UnmarshalJSON method
+func unmarshalExtra(b []byte, v interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	for k := range knownFields(reflect.TypeOf(v).Elem()) {
+		delete(raw, k)
+	}
+
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+var fieldsCache sync.Map
+
+// knownFields are cached jsonFields of t
+func knownFields(t reflect.Type) map[string]struct{} {
+	if fields, ok := fieldsCache.Load(t); ok {
+		return fields.(map[string]struct{})
+	}
+	fields := jsonFields(t)
+	fieldsCache.Store(t, fields)
+	return fields
+}
+
+func jsonFields(t reflect.Type) map[string]struct{} {
+	fields := make(map[string]struct{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields[name] = struct{}{}
+		}
+	}
+	return fields
+}
@@ -1,5 +1,11 @@
 package vo
 
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
 const (
 	// Status
 	success    = "successful"
@@ -16,66 +22,216 @@ const (
 )
 
 type TransactionResponse struct {
-	Transaction struct {
-		Message            string `json:"message"`
-		RefId              string `json:"ref_id"`
-		GatewayId          int    `json:"gateway_id"`
-		Uid                string `json:"uid"`
-		Status             string `json:"status"`
-		MessageTransaction string `json:"message_transaction"`
-		Amount             int    `json:"amount"`
-		ParentUid          string `json:"parent_uid"`
-		ReceiptUrl         string `json:"receipt_url"`
-		Currency           string `json:"currency"`
-		Type               string `json:"type"`
-		Test               bool   `json:"test"`
-	} `json:"transaction"`
+	Transaction Transaction `json:"transaction"`
+
+	// for errors
+	Response ErrorResponse `json:"response"`
+
+	//поля ответа, не описанные выше
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// TransactionsResponse is returned by status request by tracking_id
+type TransactionsResponse struct {
+	Transactions []Transaction `json:"transactions"`
 
 	// for errors
-	Response struct {
-		Message string                 `json:"message"`
-		Errors  map[string]interface{} `json:"errors"`
-	} `json:"response"`
+	Response ErrorResponse `json:"response"`
+
+	//поля ответа, не описанные выше
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type ErrorResponse struct {
+	Message string                 `json:"message"`
+	Errors  map[string]interface{} `json:"errors"`
+
+	//HTTP статус ответа с ошибкой, заполняется ApiService
+	StatusCode int `json:"-"`
+
+	//поля ответа, не описанные выше
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (tr *TransactionResponse) UnmarshalJSON(b []byte) (err error) {
+	type transactionResponse TransactionResponse
+	tr.Extra, err = unmarshalExtra(b, (*transactionResponse)(tr))
+	return err
+}
+
+func (tr *TransactionsResponse) UnmarshalJSON(b []byte) (err error) {
+	type transactionsResponse TransactionsResponse
+	tr.Extra, err = unmarshalExtra(b, (*transactionsResponse)(tr))
+	return err
+}
+
+func (r *ErrorResponse) UnmarshalJSON(b []byte) (err error) {
+	type errorResponse ErrorResponse
+	r.Extra, err = unmarshalExtra(b, (*errorResponse)(r))
+	return err
+}
+
+// UnknownFields returns fields of the response unknown to the SDK at any depth, e.g. "transaction.credit_card.new_field"
+func (tr *TransactionResponse) UnknownFields() []string {
+	fields := extraFields("", tr.Extra)
+	fields = append(fields, extraFields("response.", tr.Response.Extra)...)
+	fields = append(fields, tr.Transaction.unknownFields("transaction.")...)
+	sort.Strings(fields)
+	return fields
+}
+
+// UnknownFields returns fields of the response unknown to the SDK at any depth, e.g. "transactions[0].new_field"
+func (tr *TransactionsResponse) UnknownFields() []string {
+	fields := extraFields("", tr.Extra)
+	fields = append(fields, extraFields("response.", tr.Response.Extra)...)
+	for i, t := range tr.Transactions {
+		fields = append(fields, t.unknownFields(fmt.Sprintf("transactions[%d].", i))...)
+	}
+	sort.Strings(fields)
+	return fields
 }
 
 func (tr *TransactionResponse) IsSuccess() bool {
-	return tr.Transaction.Status == success
+	return tr.Transaction.IsSuccess()
 }
+
 func (tr *TransactionResponse) IsFailed() bool {
-	return tr.Transaction.Status == failed
+	return tr.Transaction.IsFailed()
 }
 
 func (tr *TransactionResponse) IsIncomplete() bool {
-	return tr.Transaction.Status == incomplete
+	return tr.Transaction.IsIncomplete()
 }
 
 func (tr *TransactionResponse) IsExpired() bool {
-	return tr.Transaction.Status == expired
+	return tr.Transaction.IsExpired()
 }
 
 func (tr *TransactionResponse) IsVoid() bool {
-	return tr.Transaction.Type == void
+	return tr.Transaction.IsVoid()
 }
 
 func (tr *TransactionResponse) IsAuthorization() bool {
-	return tr.Transaction.Type == authorization
+	return tr.Transaction.IsAuthorization()
 }
 
 func (tr *TransactionResponse) IsCapture() bool {
-	return tr.Transaction.Type == capture
+	return tr.Transaction.IsCapture()
 }
 
 func (tr *TransactionResponse) IsRefund() bool {
-	return tr.Transaction.Type == refund
+	return tr.Transaction.IsRefund()
 }
 
 func (tr *TransactionResponse) IsPayment() bool {
-	return tr.Transaction.Type == payment
+	return tr.Transaction.IsPayment()
 }
 
 func (tr *TransactionResponse) IsError() bool {
 	return tr.Response.Message != ""
 }
 
+func (t Transaction) IsSuccess() bool {
+	return t.Status == success
+}
+
+func (t Transaction) IsFailed() bool {
+	return t.Status == failed
+}
+
+func (t Transaction) IsIncomplete() bool {
+	return t.Status == incomplete
+}
+
+func (t Transaction) IsExpired() bool {
+	return t.Status == expired
+}
+
+func (t Transaction) IsVoid() bool {
+	return t.Type == void
+}
+
+func (t Transaction) IsAuthorization() bool {
+	return t.Type == authorization
+}
+
+func (t Transaction) IsCapture() bool {
+	return t.Type == capture
+}
+
+func (t Transaction) IsRefund() bool {
+	return t.Type == refund
+}
+
+func (t Transaction) IsPayment() bool {
+	return t.Type == payment
+}
+
+// ProcessingResult returns the section of the transaction type, e.g. Payment of payment, nil for unknown type
+func (t Transaction) ProcessingResult() *ProcessingResult {
+	switch t.Type {
+	case payment:
+		return t.Payment
+	case authorization:
+		return t.Authorization
+	case capture:
+		return t.Capture
+	case void:
+		return t.Void
+	case refund:
+		return t.Refund
+	}
+	return nil
+}
+
 //todo
 //методы информации о платеже isSuccess isFailed, isCapture, isVoid, isAuthorization, isRefund, need3ds, expDate time
+
+func (t Transaction) unknownFields(prefix string) []string {
+	fields := extraFields(prefix, t.Extra)
+
+	if t.Customer != nil {
+		fields = append(fields, extraFields(prefix+"customer.", t.Customer.Extra)...)
+	}
+	if t.CreditCard != nil {
+		fields = append(fields, extraFields(prefix+"credit_card.", t.CreditCard.Extra)...)
+	}
+	if t.BillingAddress != nil {
+		fields = append(fields, extraFields(prefix+"billing_address.", t.BillingAddress.Extra)...)
+	}
+
+	results := []struct {
+		name   string
+		result *ProcessingResult
+	}{
+		{payment, t.Payment},
+		{authorization, t.Authorization},
+		{capture, t.Capture},
+		{void, t.Void},
+		{refund, t.Refund},
+	}
+	for _, r := range results {
+		if r.result != nil {
+			fields = append(fields, extraFields(prefix+r.name+".", r.result.Extra)...)
+		}
+	}
+
+	if v := t.ThreeDSecureVerification; v != nil {
+		fields = append(fields, extraFields(prefix+"three_d_secure_verification.", v.Extra)...)
+	}
+	if v := t.AvsCvcVerification; v != nil {
+		fields = append(fields, extraFields(prefix+"avs_cvc_verification.", v.Extra)...)
+		fields = append(fields, extraFields(prefix+"avs_cvc_verification.avs_verification.", v.AvsVerification.Extra)...)
+		fields = append(fields, extraFields(prefix+"avs_cvc_verification.cvc_verification.", v.CvcVerification.Extra)...)
+	}
+
+	return fields
+}
+
+func extraFields(prefix string, extra map[string]json.RawMessage) []string {
+	fields := make([]string, 0, len(extra))
+	for k := range extra {
+		fields = append(fields, prefix+k)
+	}
+	return fields
+}
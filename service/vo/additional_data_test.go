@@ -0,0 +1,95 @@
+package vo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdditionalData_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data *AdditionalData
+		er   string
+	}{
+		{"empty", NewAdditionalData(), `{}`},
+		{"typed", NewAdditionalData().
+			WithReceiptText("Заказ 1", "Спасибо").
+			WithContract(ContractRecurring, ContractCardOnFile).
+			WithPlatformData("WooCommerce 5.1"),
+			`{"receipt_text":["Заказ 1","Спасибо"],"contract":["recurring","card_on_file"],"platform_data":"WooCommerce 5.1"}`},
+		{"customAndExtra", NewAdditionalData().
+			WithIntegrationData("bepaid-sdk").
+			WithCustom("order_source", "mobile").
+			WithCustom("promo", "custom").
+			WithExtra("promo", json.RawMessage(`{"code":"SPRING"}`)),
+			`{"integration_data":"bepaid-sdk","order_source":"mobile","promo":{"code":"SPRING"}}`},
+		{"typedFieldsWin", NewAdditionalData().
+			WithContract(ContractOneClick).
+			WithCustom("contract", "credit").
+			WithExtra("receipt_text", json.RawMessage(`"x"`)),
+			`{"contract":["oneclick"]}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := json.Marshal(tc.data)
+
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.er, string(b))
+		})
+	}
+}
+
+func TestAdditionalData_UnmarshalJSON(t *testing.T) {
+	var d AdditionalData
+
+	err := json.Unmarshal([]byte(`{"contract":["recurring"],"browser":{"screen_width":1920},"meta":{"a":1}}`), &d)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Contract{ContractRecurring}, d.Contract)
+	assert.Equal(t, 1920, d.Browser.ScreenWidth)
+	assert.Equal(t, map[string]json.RawMessage{"meta": json.RawMessage(`{"a":1}`)}, d.Extra)
+}
+
+func TestPaymentRequest_WithAdditionalDataCopies(t *testing.T) {
+	d := NewAdditionalData().
+		WithReceiptText("first").
+		WithBrowser(BrowserInfo{Language: "ru-RU"}).
+		WithCustom("k", "v")
+
+	p := NewPaymentRequest(1, "BYN", "d", "t", true, CreditCard{}).WithAdditionalData(*d)
+	a := NewAuthorizationRequest(1, "BYN", "d", "t", true, CreditCard{}).WithAdditionalData(*d)
+
+	d.ReceiptText[0] = "changed"
+	d.Browser.Language = "en-US"
+	d.Custom["k"] = "changed"
+
+	for _, got := range []*AdditionalData{p.Request.AdditionalData, a.Request.AdditionalData} {
+		assert.Equal(t, []string{"first"}, got.ReceiptText)
+		assert.Equal(t, "ru-RU", got.Browser.Language)
+		assert.Equal(t, "v", got.Custom["k"])
+	}
+}
+
+func TestPaymentRequest_CopiesDontShareAdditionalData(t *testing.T) {
+	template := *NewPaymentRequest(1099, "BYN", "d", "t", true, CreditCard{}).WithAdditionalData(*NewAdditionalData().WithCustom("k", "v"))
+
+	first, second := template, template
+	first.WithBrowserInfo(BrowserInfo{Language: "ru-RU"}).WithFiscalReceipt(fiscalReceipt())
+	second.WithBrowserInfo(BrowserInfo{Language: "en-US"})
+
+	assert.Equal(t, "ru-RU", first.Request.AdditionalData.Browser.Language)
+	assert.Equal(t, "en-US", second.Request.AdditionalData.Browser.Language)
+	assert.NotNil(t, first.Request.AdditionalData.FiscalReceipt)
+	assert.Nil(t, second.Request.AdditionalData.FiscalReceipt)
+	assert.Nil(t, template.Request.AdditionalData.Browser)
+	assert.Equal(t, "v", second.Request.AdditionalData.Custom["k"])
+
+	auth := *NewAuthorizationRequest(1099, "BYN", "d", "t", true, CreditCard{}).WithBrowserInfo(BrowserInfo{Language: "be"})
+	copied := auth
+	copied.WithFiscalReceipt(fiscalReceipt())
+	assert.Nil(t, auth.Request.AdditionalData.FiscalReceipt)
+	assert.Equal(t, "be", copied.Request.AdditionalData.Browser.Language)
+}
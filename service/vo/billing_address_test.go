@@ -0,0 +1,67 @@
+package vo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBillingAddress_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		address *BillingAddress
+		err     error
+	}{
+		{"belarus", NewBillingAddress("Ivan", "Ivanov", "Nezavisimosti 1", "BY", "Minsk", "220000"), nil},
+		{"usWithState", NewBillingAddress("John", "Doe", "1st Street", "US", "Denver", "12345").WithState("CO"), nil},
+		{"canadaWithState", NewBillingAddress("John", "Doe", "1st Street", "CA", "Toronto", "M5H").WithState("ON"), nil},
+		{"lowercaseCountry", NewBillingAddress("Ivan", "Ivanov", "Nezavisimosti 1", "by", "Minsk", "220000"), ErrBillingAddressCountry},
+		{"unknownCountry", NewBillingAddress("Ivan", "Ivanov", "Nezavisimosti 1", "XX", "Minsk", "220000"), ErrBillingAddressCountry},
+		{"emptyCountry", &BillingAddress{}, ErrBillingAddressCountry},
+		{"usWithoutState", NewBillingAddress("John", "Doe", "1st Street", "US", "Denver", "12345"), ErrBillingAddressState},
+		{"usUnknownState", NewBillingAddress("John", "Doe", "1st Street", "US", "Denver", "12345").WithState("ON"), ErrBillingAddressState},
+		{"stateForOtherCountry", NewBillingAddress("Ivan", "Ivanov", "Nezavisimosti 1", "BY", "Minsk", "220000").WithState("MI"), ErrBillingAddressState},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.address.Validate()
+			if tc.err == nil {
+				assert.Nil(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, tc.err), "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestPaymentRequest_WithBillingAddress(t *testing.T) {
+	ba := NewBillingAddress("John", "Doe", "1st Street", "US", "Denver", "12345").WithState("CO").WithPhone("+1234567")
+	r := NewPaymentRequest(100, "USD", "description", "id1", true, CreditCard{}).WithBillingAddress(*ba)
+
+	b, err := json.Marshal(r.Request.BillingAddress)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"first_name":"John","last_name":"Doe","address":"1st Street","country":"US","city":"Denver","zip":"12345","state":"CO","phone":"+1234567"}`, string(b))
+}
+
+func TestTransactionResponse_BillingAddress(t *testing.T) {
+	body := `{"transaction":{"uid":"1-310b0da80b","status":"successful",
+		"customer":{"ip":"127.0.0.1","email":"john@example.com"},
+		"billing_address":{"first_name":"John","last_name":"Doe","address":"1st Street","country":"US","city":"Denver","zip":"12345","state":"CO","phone":null}}}`
+
+	var tr TransactionResponse
+	err := json.Unmarshal([]byte(body), &tr)
+
+	assert.Nil(t, err)
+	if assert.NotNil(t, tr.Transaction.BillingAddress) {
+		assert.Equal(t, "CO", tr.Transaction.BillingAddress.State)
+		assert.Equal(t, "Denver", tr.Transaction.BillingAddress.City)
+		assert.Nil(t, tr.Transaction.BillingAddress.Validate())
+	}
+	if assert.NotNil(t, tr.Transaction.Customer) {
+		assert.Equal(t, "john@example.com", tr.Transaction.Customer.Email)
+	}
+}
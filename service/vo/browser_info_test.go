@@ -0,0 +1,78 @@
+package vo
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBrowserInfo(t *testing.T) {
+	r := httptest.NewRequest("POST", "/checkout", nil)
+	r.Header.Set("Accept", "text/html")
+	r.Header.Set("User-Agent", "Mozilla/5.0")
+	r.Header.Set("Accept-Language", "be-BY;q=1, ru;q=0.8")
+
+	form := url.Values{
+		"screen_width":       {"1920"},
+		"screen_height":      {"1080"},
+		"screen_color_depth": {"24"},
+		"window_width":       {"1280"},
+		"window_height":      {"oops"},
+		"time_zone":          {"-180"},
+		"time_zone_name":     {"Europe/Minsk"},
+		"java_enabled":       {"false"},
+	}
+
+	b := NewBrowserInfo(r, BrowserHintsFromForm(form))
+
+	assert.Equal(t, BrowserInfo{
+		AcceptHeader:      "text/html",
+		UserAgent:         "Mozilla/5.0",
+		Language:          "be-BY",
+		ScreenWidth:       1920,
+		ScreenHeight:      1080,
+		ScreenColorDepth:  24,
+		WindowWidth:       1280,
+		TimeZone:          -180,
+		TimeZoneName:      "Europe/Minsk",
+		JavascriptEnabled: true,
+	}, b)
+}
+
+func TestNewBrowserInfo_WithoutJavascript(t *testing.T) {
+	r := httptest.NewRequest("GET", "/checkout", nil)
+	r.Header.Set("Accept-Language", "*")
+
+	b := NewBrowserInfo(r, BrowserHintsFromForm(url.Values{}))
+
+	assert.False(t, b.JavascriptEnabled)
+	assert.Equal(t, "", b.Language)
+}
+
+func TestNewBrowserInfo_HintLanguageWins(t *testing.T) {
+	r := httptest.NewRequest("GET", "/checkout", nil)
+	r.Header.Set("Accept-Language", "en-US")
+
+	b := NewBrowserInfo(r, BrowserHints{Language: "ru-RU"})
+
+	assert.Equal(t, "ru-RU", b.Language)
+}
+
+func TestAuthorizationRequest_WithBrowserInfo(t *testing.T) {
+	a := NewAuthorizationRequest(1, "BYN", "d", "t", true, CreditCard{}).
+		WithAdditionalData(*NewAdditionalData().WithContract(ContractRecurring)).
+		WithBrowserInfo(BrowserInfo{Language: "ru-RU", ScreenWidth: 390})
+
+	b, err := json.Marshal(a.Request.AdditionalData)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"contract":["recurring"],"browser":{"accept_header":"","user_agent":"","language":"ru-RU",
+		"screen_width":390,"screen_height":0,"screen_color_depth":0,"window_width":0,"window_height":0,
+		"time_zone":0,"java_enabled":false,"javascript_enabled":false}}`, string(b))
+
+	p := NewPaymentRequest(1, "BYN", "d", "t", true, CreditCard{}).WithBrowserInfo(BrowserInfo{Language: "en"})
+	assert.Equal(t, "en", p.Request.AdditionalData.Browser.Language)
+}
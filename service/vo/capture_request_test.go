@@ -0,0 +1,30 @@
+package vo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureRequest_UnmarshalJSON(t *testing.T) {
+	var cr CaptureRequest
+	err := json.Unmarshal([]byte(`{"request":{"parent_uid":"1-310b0da80b","amount":50,"duplicate_check":false}}`), &cr)
+
+	assert.Nil(t, err)
+	assert.Equal(t, *NewCaptureRequest(50, "1-310b0da80b").WithDuplicateCheck(false), cr)
+}
+
+func TestCaptureRequest_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(NewCaptureRequest(50, "1-310b0da80b").WithDuplicateCheck(false))
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"request":{"parent_uid":"1-310b0da80b","amount":50,"duplicate_check":false}}`, string(b))
+}
+
+func TestCaptureRequest_DeprecatedFlatFields(t *testing.T) {
+	b, err := json.Marshal(CaptureRequest{Amount: 50, ParentUid: "1-310b0da80b", Status: "successful"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"request":{"parent_uid":"1-310b0da80b","amount":50}}`, string(b))
+}
@@ -0,0 +1,91 @@
+package vo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fiscalReceipt() FiscalReceipt {
+	return *NewFiscalReceipt(
+		*NewFiscalItem("Яблоки", 1.5, 399, TaxVat10),
+		*NewFiscalItem("Доставка", 1, 500, TaxVat20).WithPaymentSubject(SubjectService),
+	).WithEmail("client@example.com")
+}
+
+func TestFiscalReceipt_Validate(t *testing.T) {
+	r := fiscalReceipt()
+
+	assert.Equal(t, int64(599), r.Items[0].Amount)
+	assert.Equal(t, int64(1099), r.Total())
+	assert.NoError(t, r.Validate(1099))
+
+	err := r.Validate(1100)
+	assert.ErrorIs(t, err, ErrFiscalReceipt)
+	assert.ErrorContains(t, err, "total of items 1099 doesn't match amount 1100")
+}
+
+func TestFiscalReceipt_ValidateItems(t *testing.T) {
+	r := NewFiscalReceipt(
+		FiscalItem{Name: strings.Repeat("я", 129), Quantity: 0, Price: -1, Tax: "vat18", PaymentSubject: "gift"},
+		*NewFiscalItem("Скидка больше цены", 1, 100, TaxNone).WithAmount(101),
+	)
+
+	err := r.Validate(101)
+
+	var fiscalErr *FiscalReceiptError
+	assert.ErrorAs(t, err, &fiscalErr)
+	assert.Equal(t, []string{
+		"items[0]: name is longer than 128 characters",
+		"items[0]: quantity must be positive",
+		"items[0]: price must not be negative",
+		`items[0]: unknown tax "vat18"`,
+		`items[0]: unknown payment subject "gift"`,
+		"items[1]: amount 101 must be between 0 and price * quantity 100",
+	}, fiscalErr.Problems)
+
+	assert.ErrorContains(t, NewFiscalReceipt().Validate(0), "no items")
+}
+
+func TestPaymentRequest_WithFiscalReceipt(t *testing.T) {
+	receipt := fiscalReceipt()
+	pr := NewPaymentRequest(1099, "BYN", "order", "order-1", false, CreditCard{}).
+		WithBrowserInfo(BrowserInfo{Language: "ru"}).
+		WithFiscalReceipt(receipt)
+
+	// the request keeps its own copy
+	receipt.Items[0].Name = "Груши"
+
+	assert.NoError(t, pr.ValidateFiscalReceipt())
+	assert.Equal(t, "ru", pr.Request.AdditionalData.Browser.Language)
+
+	b, err := json.Marshal(pr.Request.AdditionalData.FiscalReceipt)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"items":[
+		{"name":"Яблоки","quantity":1.5,"price":399,"amount":599,"tax":"vat10","payment_subject":"commodity"},
+		{"name":"Доставка","quantity":1,"price":500,"amount":500,"tax":"vat20","payment_subject":"service"}
+	],"email":"client@example.com"}`, string(b))
+
+	ar := NewAuthorizationRequest(1000, "BYN", "order", "order-1", false, CreditCard{}).WithFiscalReceipt(fiscalReceipt())
+	assert.ErrorIs(t, ar.ValidateFiscalReceipt(), ErrFiscalReceipt)
+
+	assert.NoError(t, NewAuthorizationRequest(1000, "BYN", "order", "order-1", false, CreditCard{}).ValidateFiscalReceipt())
+}
+
+func TestAdditionalData_FiscalReceiptRoundTrip(t *testing.T) {
+	d := NewAdditionalData().WithFiscalReceipt(fiscalReceipt()).WithCustom("fiscal_receipt", "ignored")
+
+	b, err := json.Marshal(d)
+	assert.NoError(t, err)
+
+	var decoded AdditionalData
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Nil(t, decoded.Extra)
+	assert.Equal(t, fiscalReceipt(), *decoded.FiscalReceipt)
+
+	c := d.Clone()
+	c.FiscalReceipt.Items[0].Price = 1
+	assert.Equal(t, int64(399), d.FiscalReceipt.Items[0].Price)
+}
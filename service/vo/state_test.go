@@ -0,0 +1,72 @@
+package vo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionResponse_AllowedOperations(t *testing.T) {
+	tests := []struct {
+		name   string
+		typ    string
+		status string
+		state  State
+		ops    []Operation
+	}{
+		{"authorized", authorization, success, StateAuthorized, []Operation{OperationCapture, OperationVoid}},
+		{"failedAuthorization", authorization, failed, StateFailed, nil},
+		{"incompletePayment", payment, incomplete, StateIncomplete, nil},
+		{"expiredPayment", payment, expired, StateExpired, nil},
+		{"paid", payment, success, StateCaptured, []Operation{OperationRefund}},
+		{"captured", capture, success, StateCaptured, []Operation{OperationRefund}},
+		{"voided", void, success, StateVoided, nil},
+		{"refunded", refund, success, StateRefunded, nil},
+		{"unknownStatus", payment, "pending", StateUnknown, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := TransactionResponse{}
+			tr.Transaction.Type = tc.typ
+			tr.Transaction.Status = tc.status
+
+			assert.Equal(t, tc.state, tr.State())
+			assert.Equal(t, tc.ops, tr.AllowedOperations())
+		})
+	}
+}
+
+func TestBalance_State(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance Balance
+		state   State
+		allows  []Operation
+	}{
+		{"authorized", Balance{Type: authorization, Status: success, Amount: 100}, StateAuthorized, []Operation{OperationCapture, OperationVoid}},
+		{"partiallyCaptured", Balance{Type: authorization, Status: success, Amount: 100, Captured: 60}, StateCaptured, []Operation{OperationCapture, OperationVoid, OperationRefund}},
+		{"captured", Balance{Type: authorization, Status: success, Amount: 100, Captured: 100}, StateCaptured, []Operation{OperationRefund}},
+		{"partiallyRefunded", Balance{Type: authorization, Status: success, Amount: 100, Captured: 60, Refunded: 20}, StatePartiallyRefunded, []Operation{OperationCapture, OperationVoid, OperationRefund}},
+		{"refunded", Balance{Type: authorization, Status: success, Amount: 100, Captured: 100, Refunded: 100}, StateRefunded, nil},
+		{"voided", Balance{Type: authorization, Status: success, Amount: 100, Voided: 100}, StateVoided, nil},
+		{"paid", Balance{Type: payment, Status: success, Amount: 100}, StateCaptured, []Operation{OperationRefund}},
+		{"paymentRefunded", Balance{Type: payment, Status: success, Amount: 100, Refunded: 100}, StateRefunded, nil},
+		{"failed", Balance{Type: authorization, Status: failed, Amount: 100}, StateFailed, nil},
+		{"incomplete", Balance{Type: payment, Status: incomplete, Amount: 100}, StateIncomplete, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.state, tc.balance.State())
+
+			var allows []Operation
+			for _, o := range []Operation{OperationCapture, OperationVoid, OperationRefund} {
+				if tc.balance.Allows(o) {
+					allows = append(allows, o)
+				}
+			}
+			assert.Equal(t, tc.allows, allows)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package vo
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test ./service/vo -run TestTransactionResponse_Golden -update
+var update = flag.Bool("update", false, "update golden files")
+
+func TestTransactionResponse_Golden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+
+		t.Run(name, func(t *testing.T) {
+			b, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var tr TransactionResponse
+			if err = json.Unmarshal(b, &tr); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+
+			// Extra is not marshaled with Transaction, so add it explicitly
+			ar, err := json.MarshalIndent(struct {
+				Transaction Transaction                `json:"transaction"`
+				Extra       map[string]json.RawMessage `json:"extra"`
+			}{tr.Transaction, tr.Transaction.Extra}, "", "  ")
+			if err != nil {
+				t.Fatalf("MarshalIndent: %v", err)
+			}
+			ar = append(ar, '\n')
+
+			golden := filepath.Join("testdata", name+".golden")
+			if *update {
+				if err = ioutil.WriteFile(golden, ar, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			er, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(er, ar) {
+				t.Fatalf("decoded transaction differs from %s:\nER: %s\nAR: %s", golden, er, ar)
+			}
+		})
+	}
+}
+
+func TestTransactionResponse_Sections(t *testing.T) {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "payment_successful.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tr TransactionResponse
+	err = json.Unmarshal(b, &tr)
+
+	assert.Nil(t, err)
+	assert.True(t, tr.IsPayment())
+	assert.True(t, tr.IsSuccess())
+	assert.Equal(t, time.Date(2014, 6, 5, 15, 33, 52, 0, time.UTC), tr.Transaction.CreatedAt)
+	assert.True(t, tr.Transaction.PaidAt.Equal(time.Date(2014, 6, 5, 12, 33, 53, 0, time.UTC)))
+	assert.Nil(t, tr.Transaction.ExpiredAt)
+	assert.Equal(t, "0000", tr.Transaction.CreditCard.Last4)
+	assert.Equal(t, 5, tr.Transaction.CreditCard.ExpMonth)
+	assert.Equal(t, "654321", tr.Transaction.Payment.AuthCode)
+	assert.Equal(t, "999", tr.Transaction.Payment.Rrn)
+	assert.Equal(t, "Y", tr.Transaction.ThreeDSecureVerification.PaStatus)
+	assert.Equal(t, "1", tr.Transaction.AvsCvcVerification.CvcVerification.ResultCode)
+	assert.Equal(t, map[string]json.RawMessage{
+		"fraud": json.RawMessage(`"unchecked"`),
+		"id":    json.RawMessage(`"4107-310b0da80b"`),
+	}, tr.Transaction.Extra)
+}
+
+func TestTransaction_ProcessingResult(t *testing.T) {
+	refund := &ProcessingResult{Rrn: "123"}
+
+	assert.Same(t, refund, Transaction{Type: "refund", Refund: refund}.ProcessingResult())
+	assert.Nil(t, Transaction{Type: "Refund", Refund: refund}.ProcessingResult(), "types are compared exactly like IsRefund")
+	assert.Nil(t, Transaction{Type: "payment", Refund: refund}.ProcessingResult())
+	assert.Nil(t, Transaction{Type: "credit"}.ProcessingResult())
+}
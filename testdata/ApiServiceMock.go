@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: service/contracts/service.go
+// Source: service.go
 
 // Package testdata is a generated GoMock package.
 package testdata
@@ -50,6 +50,21 @@ func (mr *MockApiServiceMockRecorder) Authorizations(ctx, authorizationRequest i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorizations", reflect.TypeOf((*MockApiService)(nil).Authorizations), ctx, authorizationRequest)
 }
 
+// Balance mocks base method.
+func (m *MockApiService) Balance(ctx context.Context, uid string) (vo.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Balance", ctx, uid)
+	ret0, _ := ret[0].(vo.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Balance indicates an expected call of Balance.
+func (mr *MockApiServiceMockRecorder) Balance(ctx, uid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Balance", reflect.TypeOf((*MockApiService)(nil).Balance), ctx, uid)
+}
+
 // Capture mocks base method.
 func (m *MockApiService) Capture(ctx context.Context, captureRequest vo.CaptureRequest) (vo.TransactionResponse, error) {
 	m.ctrl.T.Helper()
@@ -64,3 +79,78 @@ func (mr *MockApiServiceMockRecorder) Capture(ctx, captureRequest interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockApiService)(nil).Capture), ctx, captureRequest)
 }
+
+// Payment mocks base method.
+func (m *MockApiService) Payment(ctx context.Context, paymentRequest vo.PaymentRequest) (vo.TransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Payment", ctx, paymentRequest)
+	ret0, _ := ret[0].(vo.TransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Payment indicates an expected call of Payment.
+func (mr *MockApiServiceMockRecorder) Payment(ctx, paymentRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Payment", reflect.TypeOf((*MockApiService)(nil).Payment), ctx, paymentRequest)
+}
+
+// Refund mocks base method.
+func (m *MockApiService) Refund(ctx context.Context, refundRequest vo.RefundRequest) (vo.TransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refund", ctx, refundRequest)
+	ret0, _ := ret[0].(vo.TransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refund indicates an expected call of Refund.
+func (mr *MockApiServiceMockRecorder) Refund(ctx, refundRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refund", reflect.TypeOf((*MockApiService)(nil).Refund), ctx, refundRequest)
+}
+
+// StatusByTrackingId mocks base method.
+func (m *MockApiService) StatusByTrackingId(ctx context.Context, trackingId string) (vo.TransactionsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatusByTrackingId", ctx, trackingId)
+	ret0, _ := ret[0].(vo.TransactionsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatusByTrackingId indicates an expected call of StatusByTrackingId.
+func (mr *MockApiServiceMockRecorder) StatusByTrackingId(ctx, trackingId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusByTrackingId", reflect.TypeOf((*MockApiService)(nil).StatusByTrackingId), ctx, trackingId)
+}
+
+// StatusByUid mocks base method.
+func (m *MockApiService) StatusByUid(ctx context.Context, uid string) (vo.TransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StatusByUid", ctx, uid)
+	ret0, _ := ret[0].(vo.TransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatusByUid indicates an expected call of StatusByUid.
+func (mr *MockApiServiceMockRecorder) StatusByUid(ctx, uid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatusByUid", reflect.TypeOf((*MockApiService)(nil).StatusByUid), ctx, uid)
+}
+
+// Void mocks base method.
+func (m *MockApiService) Void(ctx context.Context, voidRequest vo.VoidRequest) (vo.TransactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Void", ctx, voidRequest)
+	ret0, _ := ret[0].(vo.TransactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Void indicates an expected call of Void.
+func (mr *MockApiServiceMockRecorder) Void(ctx, voidRequest interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Void", reflect.TypeOf((*MockApiService)(nil).Void), ctx, voidRequest)
+}